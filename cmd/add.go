@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+var (
+	addFrom string
+	addTo   string
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add [flags] Project.Task [+tag...] 'Description'",
+	Short: "Add a completed timeslip for past work",
+	Long: `Add a completed timeslip for work done in the past, such as when away
+from the keyboard. Both the --from and --to times are required.
+
+The times can be given as '15:04' for today, or '2006-01-02 15:04'.
+
+Example:
+
+$ tw add MyProject.Meeting --from "2026-10-16 09:00" --to "2026-10-16 11:30" "Planning"`,
+	Args:                  cobra.MinimumNArgs(2),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		tags, description, err := timeslip.ParseTagDescriptionArgs(args[1:])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		slip, err := addCompletedSlip(args[0], tags, description)
+
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		if slip != nil {
+			fmt.Println(slip)
+		}
+	},
+}
+
+func init() {
+	addCmd.Flags().StringVar(&addFrom, "from", "", `time the work was started`)
+	addCmd.Flags().StringVar(&addTo, "to", "", `time the work was finished`)
+
+	rootCmd.AddCommand(addCmd)
+}
+
+func addCompletedSlip(name string, tags []string, description string) (*timeslip.Slip, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("add")
+	if err != nil {
+		return nil, err
+	}
+
+	if addFrom == "" || addTo == "" {
+		return nil, fmt.Errorf("both --from and --to times are required")
+	}
+
+	from, err := parseTimestamp(addFrom, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parseTimestamp(addTo, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	slip, err := timeslip.NewCompleted(name, from, to, description, tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.SaveCompleted(slip.Project, slip.ToJson()); err != nil {
+		return slip, err
+	}
+
+	if err := m.Record(op, nil, slip.ToJson()); err != nil {
+		return slip, err
+	}
+
+	return slip, nil
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
 	"github.com/mrcook/time_warrior/timeslip"
 )
 
@@ -15,14 +14,15 @@ var adjustCmd = &cobra.Command{
 	Use:   "adjust DURATION",
 	Short: "Adjust the time worked on a timeslip",
 	Long: `Increase or decrease the time worked on a timeslip using a
-duration string based on time units of hours, minutes, or seconds.
+duration string based on time units of days, hours, minutes, or seconds.
 
-The DURATION string should be in the format of '10m' - a decimal
-number followed by a single time unit character (no spaces).
+The DURATION string can be given in any of these formats (no spaces):
 
-Allowed units are 'h', 'm', and 's'.
+  - numbers with a time unit, in the order d, h, m, s: '2h15m30s', '1.5h'
+  - a clock duration of hours and minutes: '01:30'
+  - a number without a time unit, taken as minutes: '90'
 
-Example strings: '72m', '2h', '130s', '30m', '720s'
+Example strings: '72m', '2h', '130s', '1h30m', '0.5h', '01:30', '90'
 
 To subtract a value, specify the -n (negative) flag.`,
 	Args: cobra.ExactArgs(1),
@@ -55,9 +55,22 @@ func init() {
 // Adjust a pending timeslip +/- a given amount
 // Only paused timeslips should be adjusted
 func adjust(adjustment string) (*timeslip.Slip, error) {
-	m := manager.NewFromConfig(initializeConfig())
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("adjust")
+	if err != nil {
+		return nil, err
+	}
 
-	if !m.PendingTimeSlipExists() {
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
 		return nil, fmt.Errorf("no pending timeslip found")
 	}
 
@@ -79,5 +92,9 @@ func adjust(adjustment string) (*timeslip.Slip, error) {
 		return slip, fmt.Errorf("timeslip may not have been saved: %v", err)
 	}
 
+	if err := m.Record(op, nil, nil); err != nil {
+		return slip, err
+	}
+
 	return slip, nil
 }
@@ -5,16 +5,28 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
+	"github.com/mrcook/time_warrior/timeslip"
 )
 
 var deleteCmd = &cobra.Command{
-	Use:                   "delete",
-	Short:                 "Delete an in progress timeslip",
-	Args:                  cobra.NoArgs,
+	Use:   "delete [UUID]",
+	Short: "Delete the in progress timeslip, or a completed one",
+	Long: `Delete the in progress timeslip, or a completed timeslip found by its UUID,
+or the start of it as shown by the log command.
+
+Deleted timeslips are moved to the trash, and can be restored with
+'tw trash restore UUID'.`,
+	Args:                  cobra.MaximumNArgs(1),
 	DisableFlagsInUseLine: true,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := deletePendingTimeSlip(); err != nil {
+		var err error
+		if len(args) > 0 {
+			err = deleteCompletedTimeSlip(args[0])
+		} else {
+			err = deletePendingTimeSlip()
+		}
+
+		if err != nil {
 			fmt.Println(err)
 		} else {
 			fmt.Println("Deleted!")
@@ -27,12 +39,57 @@ func init() {
 }
 
 func deletePendingTimeSlip() error {
-	m := manager.NewFromConfig(initializeConfig())
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	if !m.PendingTimeSlipExists() {
+	op, err := m.BeginOperation("delete")
+	if err != nil {
+		return err
+	}
+
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return err
+	}
+	if !exists {
 		return fmt.Errorf("no pending timeslip found")
 	}
 
-	err := m.DeletePending()
-	return err
+	if err := m.TrashPending(); err != nil {
+		return err
+	}
+
+	return m.Record(op, nil, nil)
+}
+
+func deleteCompletedTimeSlip(uuid string) error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("delete")
+	if err != nil {
+		return err
+	}
+
+	data, err := m.TrashCompleted(uuid)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Record(op, data, nil); err != nil {
+		return err
+	}
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err == nil {
+		fmt.Println(slip)
+	}
+
+	return nil
 }
@@ -5,14 +5,16 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
 	"github.com/mrcook/time_warrior/timeslip"
 )
 
 var doneCmd = &cobra.Command{
-	Use:                   "done 'Description'",
-	Short:                 "Mark current timeslip as completed",
-	Long:                  `Mark the current timeslip as done, providing a useful description.`,
+	Use:   "done [flags] 'Description'",
+	Short: "Mark current timeslip as completed",
+	Long: `Mark the current timeslip as done, providing a useful description.
+
+A forgotten done can be back-dated using --at or --ago, but not to
+before the timeslip was started or resumed.`,
 	Aliases:               []string{"d"},
 	Args:                  cobra.ExactArgs(1),
 	DisableFlagsInUseLine: true,
@@ -30,13 +32,28 @@ var doneCmd = &cobra.Command{
 }
 
 func init() {
+	addTimestampFlags(doneCmd)
+
 	rootCmd.AddCommand(doneCmd)
 }
 
 func done(description string) (*timeslip.Slip, error) {
-	m := manager.NewFromConfig(initializeConfig())
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("done")
+	if err != nil {
+		return nil, err
+	}
 
-	if !m.PendingTimeSlipExists() {
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
 		return nil, fmt.Errorf("no pending timeslip found")
 	}
 
@@ -50,13 +67,20 @@ func done(description string) (*timeslip.Slip, error) {
 		return nil, err
 	}
 
-	slip.Done(description)
+	at, err := transitionTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := slip.DoneAt(at, description); err != nil {
+		return nil, err
+	}
 
-	if err := m.SaveCompleted(slip.Project, slip.ToJson()); err != nil {
+	if err := m.CompletePending(slip.Project, slip.ToJson()); err != nil {
 		return slip, err
 	}
 
-	if err := m.DeletePending(); err != nil {
+	if err := m.Record(op, nil, slip.ToJson()); err != nil {
 		return slip, err
 	}
 
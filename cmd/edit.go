@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+var (
+	editDescription string
+	editTask        string
+	editStarted     string
+	editFinished    string
+	editWorked      string
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [flags] UUID",
+	Short: "Edit a completed timeslip",
+	Long: `Edit a completed timeslip, found by its UUID, or the start of it as shown by
+the log command.
+
+The description, task, started and finished times, and the time worked can
+be changed with flags. Example: tw edit 10aad44c --worked 2h15m
+
+Without any flags the timeslip JSON is opened in your $VISUAL or $EDITOR.
+
+The edited timeslip must be finished after it was started, and the time
+worked can not be more than the time in between.`,
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		slip, err := editTimeSlip(cmd, args[0])
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Println(slip)
+		}
+	},
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editDescription, "description", "d", "", `a new description`)
+	editCmd.Flags().StringVar(&editTask, "task", "", `a new task name, use "" for no task`)
+	editCmd.Flags().StringVar(&editStarted, "started", "", `a new start time, e.g. "2026-10-17 09:30"`)
+	editCmd.Flags().StringVar(&editFinished, "finished", "", `a new finish time, e.g. "2026-10-17 16:45"`)
+	editCmd.Flags().StringVar(&editWorked, "worked", "", `a new time worked, e.g. "2h15m"`)
+
+	rootCmd.AddCommand(editCmd)
+}
+
+// The data directory is not locked while the timeslip is being edited, so
+// the timeslip is checked to be unchanged before saving.
+func editTimeSlip(cmd *cobra.Command, uuid string) (*timeslip.Slip, error) {
+	data, err := findCompletedSlip(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return nil, err
+	}
+
+	var edited *timeslip.Slip
+	if !editFlagsChanged(cmd) {
+		edited, err = editInEditor(slip)
+	} else {
+		edited, err = editWithFlags(cmd, slip)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(edited.ToJson(), slip.ToJson()) {
+		return nil, fmt.Errorf("no changes made to the timeslip")
+	}
+
+	if edited.Finished > int(time.Now().Unix()) {
+		return nil, fmt.Errorf("time can not be in the future")
+	}
+	if err := edited.Validate(); err != nil {
+		return nil, err
+	}
+	edited.Modified = int(time.Now().Unix())
+
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	current, err := m.FindCompleted(slip.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(current, data) {
+		return nil, fmt.Errorf("timeslip was changed while it was being edited, no changes were saved")
+	}
+
+	op, err := m.BeginOperation("edit")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.ReplaceCompleted(edited.ToJson()); err != nil {
+		return nil, err
+	}
+
+	if err := m.Record(op, data, edited.ToJson()); err != nil {
+		return edited, err
+	}
+
+	return edited, nil
+}
+
+// Returns the completed timeslip with a UUID starting with the prefix.
+func findCompletedSlip(uuid string) ([]byte, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.FindCompleted(uuid)
+}
+
+// Returns true if any of the edit flags were given. The global flags are not
+// edits, so they do not count.
+func editFlagsChanged(cmd *cobra.Command) bool {
+	for _, name := range []string{"description", "task", "started", "finished", "worked"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Applies the changes given by the flags to a copy of the timeslip.
+func editWithFlags(cmd *cobra.Command, slip *timeslip.Slip) (*timeslip.Slip, error) {
+	edited := *slip
+	now := time.Now()
+
+	if cmd.Flags().Changed("description") {
+		edited.Description = editDescription
+	}
+	if cmd.Flags().Changed("task") {
+		edited.Task = editTask
+	}
+
+	started, finished, workedTime := slip.Started, slip.Finished, slip.Worked
+
+	if cmd.Flags().Changed("started") {
+		t, err := parseTimestamp(editStarted, now)
+		if err != nil {
+			return nil, err
+		}
+		started = int(t.Unix())
+	}
+	if cmd.Flags().Changed("finished") {
+		t, err := parseTimestamp(editFinished, now)
+		if err != nil {
+			return nil, err
+		}
+		finished = int(t.Unix())
+	}
+	if cmd.Flags().Changed("worked") {
+		w := worked.WorkTime{}
+		if err := w.FromString(editWorked); err != nil {
+			return nil, err
+		}
+		workedTime = w.ToSeconds()
+	}
+
+	if started != slip.Started || finished != slip.Finished || workedTime != slip.Worked {
+		if err := edited.Retime(started, finished, workedTime); err != nil {
+			return nil, err
+		}
+	}
+
+	return &edited, nil
+}
+
+// Opens the timeslip JSON in the user's editor, returning the edited timeslip.
+// When the times are changed without changing the segments, the segments are
+// fitted to the new times.
+func editInEditor(slip *timeslip.Slip) (*timeslip.Slip, error) {
+	original, err := json.MarshalIndent(slip, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "tw-edit-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(append(original, '\n')); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := runEditor(file.Name()); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(bytes.TrimSpace(data), original) {
+		return nil, fmt.Errorf("no changes made to the timeslip")
+	}
+
+	edited := &timeslip.Slip{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(edited); err != nil {
+		return nil, fmt.Errorf("invalid timeslip JSON: %v", err)
+	}
+
+	// tags are saved in lower case, as with the other commands
+	if edited.Tags, err = timeslip.ParseTags(edited.Tags); err != nil {
+		return nil, err
+	}
+
+	if edited.UUID != slip.UUID {
+		return nil, fmt.Errorf("the UUID of a timeslip can not be changed")
+	}
+	if edited.Project != slip.Project {
+		return nil, fmt.Errorf("the project of a timeslip can not be changed")
+	}
+
+	timesChanged := edited.Started != slip.Started || edited.Finished != slip.Finished || edited.Worked != slip.Worked
+	if timesChanged && reflect.DeepEqual(edited.Segments, slip.Segments) {
+		retimed := *edited
+		retimed.Segments = slip.Segments
+		if err := retimed.Retime(edited.Started, edited.Finished, edited.Worked); err != nil {
+			return nil, err
+		}
+		edited = &retimed
+	}
+
+	return edited, nil
+}
+
+// Runs the $VISUAL or $EDITOR command on the file, falling back to vi.
+func runEditor(filename string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	args := strings.Fields(editor)
+	c := exec.Command(args[0], append(args[1:], filename)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("unable to run the editor: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/manager"
+	"github.com/mrcook/time_warrior/reports"
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+var (
+	logPeriod  string
+	logFrom    string
+	logTo      string
+	logLimit   int
+	logReverse bool
+	logFormat  string
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log [flags] [PROJECT]",
+	Short: "List individual timeslips in time order",
+	Long: `List the individual timeslips, oldest first, with their start and end times,
+time worked, status, short UUID, and description. The pending timeslip is
+listed along with the completed ones.
+
+Use the --period, or --from/--to, flags to list the timeslips for a time
+period, as with the report command. The --limit flag lists only the most
+recent timeslips, and --reverse lists the newest first.
+
+Examples:
+
+$ tw log -p w
+=> All timeslips worked on this week.
+
+$ tw log --limit 10 --reverse MyProject
+=> The ten most recent MyProject timeslips, newest first.
+`,
+	Args:                  cobra.MaximumNArgs(1),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := ""
+		if len(args) > 0 {
+			projectName = args[0]
+		}
+		if err := listTimeslips(projectName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	logCmd.Flags().StringVarP(&logPeriod, "period", "p", "", `list timeslips for the time period, e.g. w, 1m, 'last 7 days'.`)
+	logCmd.Flags().StringVar(&logFrom, "from", "", `list timeslips from this date, e.g. 2026-09-01.`)
+	logCmd.Flags().StringVar(&logTo, "to", "", `list timeslips up to this date (default today).`)
+	logCmd.Flags().IntVarP(&logLimit, "limit", "n", 0, `list only the most recent timeslips.`)
+	logCmd.Flags().BoolVarP(&logReverse, "reverse", "r", false, `list the newest timeslips first.`)
+	logCmd.Flags().StringVar(&logFormat, "format", "text", `output format: text, json.`)
+
+	rootCmd.AddCommand(logCmd)
+}
+
+func listTimeslips(projectName string) error {
+	p, err := parsePeriod(logPeriod, logFrom, logTo)
+	if err != nil {
+		return err
+	}
+	format, err := reports.ParseFormat(logFormat)
+	if err != nil {
+		return err
+	}
+	if logLimit < 0 {
+		return fmt.Errorf("the limit can not be negative")
+	}
+
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var from, to time.Time
+	if p.IsSet() {
+		from, to = p.From(), p.To()
+	}
+
+	pending, hasPending := pendingSlip(m)
+	if hasPending && projectName != "" && !manager.SameProject(pending.Project, projectName) {
+		hasPending = false
+	}
+
+	var projects []string
+	if projectName == "" {
+		if projects, err = m.Projects(); err != nil {
+			return err
+		}
+	} else if m.ProjectExists(projectName) {
+		projects = []string{projectName}
+	} else if !hasPending {
+		return fmt.Errorf("project not found")
+	}
+
+	log := reports.Log{}
+
+	for _, project := range projects {
+		err := m.Slips(project, from, to, func(data []byte) error {
+			slip := &timeslip.Slip{}
+			if err := timeslip.Unmarshal(data, slip); err != nil {
+				return nil // unreadable timeslips are listed by the report command
+			}
+			log.Add(slip)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasPending {
+		if !p.IsSet() || pending.TimeWorkedBetween(from, to.Add(time.Second)) > 0 {
+			log.Add(pending)
+		}
+	}
+
+	log.Sort(logReverse, logLimit)
+
+	return log.Render(os.Stdout, format)
+}
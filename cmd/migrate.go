@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateStorage string
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --storage BACKEND",
+	Short: "Copy all timeslips to another storage backend",
+	Long: `Copy the pending, stashed, and completed timeslips to another storage backend
+in the data directory, so that years of timeslips can be moved to the embedded
+database. The other backend must not have any timeslips yet.
+
+Example: tw migrate --storage bolt
+
+The timeslips are left in the current backend. Once copied, set the storage
+in the config file, or TW_STORAGE, to use the new backend.`,
+	Args:                  cobra.NoArgs,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := migrateTimeSlips(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&migrateStorage, "storage", "", `the storage backend to copy to: jsonl or bolt`)
+	_ = migrateCmd.MarkFlagRequired("storage")
+
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func migrateTimeSlips() error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	copied, err := m.Migrate(initializeConfig(), migrateStorage)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Copied %d completed timeslips to the %s storage backend.\n", copied, migrateStorage)
+	fmt.Printf("Set 'storage = \"%s\"' in the config file, or TW_STORAGE=%s, to use it.\n", migrateStorage, migrateStorage)
+
+	return nil
+}
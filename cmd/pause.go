@@ -5,13 +5,16 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
 	"github.com/mrcook/time_warrior/timeslip"
 )
 
 var pauseCmd = &cobra.Command{
-	Use:                   "pause",
-	Short:                 "Pause a started timeslip",
+	Use:   "pause [flags]",
+	Short: "Pause a started timeslip",
+	Long: `Pause a started timeslip.
+
+A forgotten pause can be back-dated using --at or --ago, but not to
+before the timeslip was started or resumed.`,
 	Aliases:               []string{"p"},
 	Args:                  cobra.NoArgs,
 	DisableFlagsInUseLine: true,
@@ -27,11 +30,22 @@ var pauseCmd = &cobra.Command{
 }
 
 func init() {
+	addTimestampFlags(pauseCmd)
+
 	rootCmd.AddCommand(pauseCmd)
 }
 
 func pauseTimeSlip() (*timeslip.Slip, error) {
-	m := manager.NewFromConfig(initializeConfig())
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("pause")
+	if err != nil {
+		return nil, err
+	}
 
 	slipJSON, slipError := m.PendingTimeSlip()
 	if slipError != nil {
@@ -47,7 +61,12 @@ func pauseTimeSlip() (*timeslip.Slip, error) {
 		return nil, err
 	}
 
-	if err := slip.Pause(); err != nil {
+	at, err := transitionTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := slip.PauseAt(at); err != nil {
 		return nil, err
 	}
 
@@ -55,5 +74,9 @@ func pauseTimeSlip() (*timeslip.Slip, error) {
 		return nil, err
 	}
 
+	if err := m.Record(op, nil, nil); err != nil {
+		return slip, err
+	}
+
 	return slip, nil
 }
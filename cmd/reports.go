@@ -2,15 +2,32 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
 	"github.com/mrcook/time_warrior/reports"
+	"github.com/mrcook/time_warrior/reports/period"
 	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/worked"
 )
 
-var period = "t"
+var (
+	timePeriod       string
+	fromDate         string
+	toDate           string
+	formatName       string
+	showGrid         bool
+	showDescriptions bool
+	dedupe           bool
+	groupBy          string
+	tags             []string
+	excludeTags      []string
+	roundTo          string
+	roundMode        string
+	roundScope       string
+)
 
 var reportCmd = &cobra.Command{
 	Use:   "report [flags] PROJECT",
@@ -20,6 +37,13 @@ var reportCmd = &cobra.Command{
 Time Periods:
   - t=today, w=this week, m=this month, y=this year.
   - 1d=yesterday, 1w=last week, 1m=last month, 1y=last year.
+  - fq=this fiscal quarter, fy=this fiscal year, 1fq=last fiscal quarter,
+    1fy=last fiscal year.
+  - 3d, 2w, 6m, 2y: several whole days, weeks, months, or years before
+    the current one.
+  - expressions: 'last 7 days', 'this quarter', 'last quarter', 'Q3 2025',
+    '2026-W41' (ISO week), '2026-09', '2026', 'monday..friday', and
+    '2026-09-01..2026-09-15'.
 
 Project name omitted: report is generated showing the total time worked for
 each Project.
@@ -27,7 +51,35 @@ each Project.
 Project name specified: report is generated showing the total time worked
 for that Project, followed by a break down of time worked for each Task.
 
-Time Unit incorrect or missing: report is generated using *all* timeslips.
+Time Unit missing: report is generated using *all* timeslips, unless a
+default_period is set in the config file. An unknown time unit is an error.
+
+Date Range: use --from and --to to report on any range of days, e.g.
+--from 2026-09-01 --to 2026-09-15. Without --to the range ends today.
+
+Descriptions: use --descriptions (or --verbose) to list what was done on each
+task of a project report, with the date and time worked. Add --dedupe to
+combine repeated descriptions.
+
+Tags: only timeslips with all the --tag tags, and none of the --exclude-tag
+tags, are included. Use --by tag to show the total time worked for each tag.
+
+Rounding: use --round to round the time worked to an increment for billing,
+e.g. '6m' or '15m'. The --round-mode is one of: up, down, nearest (default).
+The --round-scope applies rounding to each timeslip (default), the time
+worked on each task per day, or only the report total: slip, day, total.
+
+Timesheet: use --by day to list each day worked on in the period, with the
+time worked on each task, and a subtotal per day.
+
+Grid: use --grid to show a timesheet grid for each week, with the projects,
+or tasks when a project is given, as rows and the days as columns. Weeks
+start on the week_start day from the config file.
+
+Output: use --format to write the report as json, csv, or markdown, for use
+in spreadsheets or scripts. The JSON and CSV include the time worked in
+seconds along with the formatted duration, the period dates, and the
+pending timeslip.
 
 Examples:
 
@@ -38,6 +90,10 @@ $ tw report -p 1d MyProject
 => Report for all tasks in MyProject, with the total time worked per task,
    for yesterday.
 
+$ tw report -p w --by tag --exclude-tag meeting
+=> Report showing the total time worked per tag for the current week,
+   excluding all meetings.
+
 Further instructions and examples can be found in the README.
 `,
 	DisableFlagsInUseLine: true,
@@ -46,41 +102,170 @@ Further instructions and examples can be found in the README.
 		if len(args) > 0 {
 			projectName = args[0]
 		}
-		generateReport(projectName, period)
+		if err := generateReport(projectName, timePeriod); err != nil {
+			fmt.Println(err)
+		}
 	},
 }
 
 func init() {
-	reportCmd.Flags().StringVarP(&period, "period", "p", "", `report for the time period: t, 1d, w, m, y.`)
+	reportCmd.Flags().StringVarP(&timePeriod, "period", "p", "", `report for the time period: t, 1d, w, m, y, or an expression.`)
+	reportCmd.Flags().StringVar(&fromDate, "from", "", `report from this date, e.g. 2026-09-01.`)
+	reportCmd.Flags().StringVar(&toDate, "to", "", `report up to this date (default today).`)
+	reportCmd.Flags().StringVar(&formatName, "format", "text", `output format: text, json, csv, markdown.`)
+	reportCmd.Flags().BoolVar(&showGrid, "grid", false, `show a weekly timesheet grid of days by project or task.`)
+	reportCmd.Flags().BoolVar(&showDescriptions, "descriptions", false, `list the timeslip descriptions for each task.`)
+	reportCmd.Flags().BoolVarP(&showDescriptions, "verbose", "v", false, `same as --descriptions.`)
+	reportCmd.Flags().BoolVar(&dedupe, "dedupe", false, `combine repeated descriptions.`)
+	reportCmd.Flags().StringVar(&groupBy, "by", "", `group the report totals by: tag, day.`)
+	reportCmd.Flags().StringSliceVar(&tags, "tag", nil, `only include timeslips with this tag.`)
+	reportCmd.Flags().StringSliceVar(&excludeTags, "exclude-tag", nil, `exclude timeslips with this tag.`)
+	reportCmd.Flags().StringVar(&roundTo, "round", "", `round the time worked to this increment, e.g. 15m.`)
+	reportCmd.Flags().StringVar(&roundMode, "round-mode", "nearest", `rounding direction: up, down, nearest.`)
+	reportCmd.Flags().StringVar(&roundScope, "round-scope", "slip", `apply rounding per: slip, day, total.`)
 
 	rootCmd.AddCommand(reportCmd)
 }
 
-func generateReport(projectName, period string) {
-	m := manager.NewFromConfig(initializeConfig())
+func generateReport(projectName, timePeriod string) error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
 	pendingSlip := timeslip.Slip{}
 	if pending, err := m.PendingTimeSlip(); err == nil {
 		_ = timeslip.Unmarshal(pending, &pendingSlip)
 	}
 
-	report := reports.New(period)
-	if pendingSlip.TotalTimeWorked() > 0 {
+	if timePeriod == "" && fromDate == "" && toDate == "" {
+		timePeriod = initializeConfig().DefaultPeriod()
+	}
+	reportPeriod, err := parsePeriod(timePeriod, fromDate, toDate)
+	if err != nil {
+		return err
+	}
+	format, err := reports.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	report := reports.New(reportPeriod)
+
+	report.Grid = showGrid
+	report.Descriptions = showDescriptions
+	report.DedupeDescriptions = dedupe
+
+	switch groupBy {
+	case "":
+	case "tag":
+		report.GroupByTag = true
+	case "day":
+		report.GroupByDay = true
+	default:
+		return fmt.Errorf("unknown report grouping, got '%s'", groupBy)
+	}
+
+	if report.Filter.Tags, err = timeslip.ParseTags(tags); err != nil {
+		return err
+	}
+	if report.Filter.ExcludeTags, err = timeslip.ParseTags(excludeTags); err != nil {
+		return err
+	}
+
+	if roundTo != "" {
+		if report.Rounding, err = parseRounding(roundTo, roundMode, roundScope); err != nil {
+			return err
+		}
+	}
+
+	if pendingSlip.TotalTimeWorked() > 0 && report.Filter.Matches(pendingSlip.Tags) {
 		report.PendingTimeslip = pendingSlip
 	}
 
+	var from, to time.Time
+	if report.TimePeriod().IsSet() {
+		from, to = report.TimePeriod().From(), report.TimePeriod().To()
+	}
+
+	var projects []string
 	if projectName == "" {
-		for _, filename := range m.AllProjectFilenames() {
-			report.ProcessProjectFile(filename)
+		if projects, err = m.Projects(); err != nil {
+			return err
 		}
 	} else {
-		filename, ok := m.ProjectFilename(projectName)
-		if !ok {
-			fmt.Println("project file not found")
-			return
+		if !m.ProjectExists(projectName) {
+			return fmt.Errorf("project not found")
 		}
-		report.ProcessProjectFile(filename)
+		projects = []string{projectName}
+	}
+
+	for _, project := range projects {
+		report.ProcessProject(func(fn func(slip []byte) error) error {
+			return m.Slips(project, from, to, fn)
+		})
+	}
+
+	return report.Summary().Render(os.Stdout, format)
+}
+
+// Returns the time period, either from the --from/--to dates, or the period
+// code or expression. When none are given the period is unbounded.
+func parsePeriod(unit, from, to string) (*period.Period, error) {
+	if from == "" && to == "" {
+		return period.Parse(unit)
+	}
+
+	if unit != "" {
+		return nil, fmt.Errorf("the --period flag can not be used with --from/--to")
+	}
+	if from == "" {
+		return nil, fmt.Errorf("a --from date is required when using --to")
+	}
+
+	fromTime, err := parseDate(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toTime := time.Now()
+	if to != "" {
+		if toTime, err = parseDate(to); err != nil {
+			return nil, err
+		}
+	}
+
+	return period.Range(fromTime, toTime)
+}
+
+func parseDate(value string) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return t, fmt.Errorf("invalid date, expected '2006-01-02', got '%s'", value)
+	}
+	return t, nil
+}
+
+func parseRounding(increment, mode, scope string) (reports.Rounding, error) {
+	r := reports.Rounding{}
+
+	w := worked.WorkTime{}
+	if err := w.FromString(increment); err != nil {
+		return r, err
+	}
+	if w.ToSeconds() <= 0 {
+		return r, fmt.Errorf("rounding increment must be greater than zero")
+	}
+	r.Increment = w.ToSeconds()
+
+	var err error
+	if r.Mode, err = worked.ParseRoundingMode(mode); err != nil {
+		return r, err
+	}
+	if r.Scope, err = reports.ParseRoundingScope(scope); err != nil {
+		return r, err
 	}
 
-	report.PrintReport()
+	return r, nil
 }
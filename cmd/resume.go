@@ -5,13 +5,16 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
 	"github.com/mrcook/time_warrior/timeslip"
 )
 
 var resumeCmd = &cobra.Command{
-	Use:                   "resume",
-	Short:                 "Resume a paused timeslip",
+	Use:   "resume [flags]",
+	Short: "Resume a paused timeslip",
+	Long: `Resume a paused timeslip.
+
+A forgotten resume can be back-dated using --at or --ago, but not to
+before the timeslip was paused.`,
 	Aliases:               []string{"r"},
 	Args:                  cobra.NoArgs,
 	DisableFlagsInUseLine: true,
@@ -26,11 +29,22 @@ var resumeCmd = &cobra.Command{
 }
 
 func init() {
+	addTimestampFlags(resumeCmd)
+
 	rootCmd.AddCommand(resumeCmd)
 }
 
 func resumeTimeSlip() (*timeslip.Slip, error) {
-	m := manager.NewFromConfig(initializeConfig())
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("resume")
+	if err != nil {
+		return nil, err
+	}
 
 	slipJSON, slipError := m.PendingTimeSlip()
 	if slipError != nil {
@@ -42,7 +56,12 @@ func resumeTimeSlip() (*timeslip.Slip, error) {
 		return nil, err
 	}
 
-	if err := slip.Resume(); err != nil {
+	at, err := transitionTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := slip.ResumeAt(at); err != nil {
 		return nil, err
 	}
 
@@ -50,5 +69,9 @@ func resumeTimeSlip() (*timeslip.Slip, error) {
 		return nil, err
 	}
 
+	if err := m.Record(op, nil, nil); err != nil {
+		return slip, err
+	}
+
 	return slip, nil
 }
@@ -9,7 +9,9 @@ import (
 
 	"github.com/mrcook/time_warrior/configuration"
 	"github.com/mrcook/time_warrior/manager"
+	"github.com/mrcook/time_warrior/reports/period"
 	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/worked"
 )
 
 // rootCmd represents the base command when called without any sub commands
@@ -19,29 +21,58 @@ var rootCmd = &cobra.Command{
 	Short:   "TimeWarrior: a CLI based time tracking tool",
 	Long: `TimeWarrior is a command line time tracking tool for developers and freelance
 workers who need to track time worked on their client and personal projects.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return loadConfig()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		m := manager.NewFromConfig(initializeConfig())
-		if !m.PendingTimeSlipExists() {
-			cmd.Help()
+		m, unlock, err := lockManager()
+		if err != nil {
+			fmt.Println(err)
 			return
 		}
+		defer unlock()
 
-		slipJSON, slipError := m.PendingTimeSlip()
-		if slipError != nil {
-			fmt.Println(slipError)
+		stashed, err := m.StashedTimeSlips()
+		if err != nil {
+			fmt.Println(err)
 			return
 		}
 
-		slip := &timeslip.Slip{}
-		if err := timeslip.Unmarshal(slipJSON, slip); err != nil {
+		exists, err := m.PendingTimeSlipExists()
+		if err != nil {
 			fmt.Println(err)
 			return
 		}
 
-		fmt.Println(slip)
+		if !exists && len(stashed) == 0 {
+			cmd.Help()
+			return
+		}
+
+		if exists {
+			slip, ok := pendingSlip(m)
+			if !ok {
+				fmt.Println("unable to read the pending timeslip")
+				return
+			}
+			fmt.Println(slip)
+		}
+
+		printStashedSlips(stashed)
 	},
 }
 
+var (
+	config         *configuration.Config
+	dataDirectory  string
+	durationFormat string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&dataDirectory, "data-dir", "", `directory for the timeslip data files`)
+	rootCmd.PersistentFlags().StringVar(&durationFormat, "duration-format", "", `format for worked time: default, decimal, clock, iso8601`)
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -51,7 +82,116 @@ func Execute() {
 	}
 }
 
-// initializeConfig reads in config file and ENV variables if set.
+// lockManager returns a manager holding the data directory lock, along with
+// the function to release it and close the storage. Any timeslip left half
+// completed by a previous run is completed first.
+func lockManager() (*manager.Manager, func(), error) {
+	m, err := manager.NewFromConfig(initializeConfig())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unlock, err := m.Lock()
+	if err != nil {
+		m.Close()
+		return nil, nil, err
+	}
+
+	release := func() {
+		unlock()
+		m.Close()
+	}
+
+	recovered, err := m.Recover()
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	if recovered {
+		fmt.Println("An interrupted timeslip was completed.")
+	}
+
+	return m, release, nil
+}
+
+// pendingSlip returns the pending timeslip, if there is one.
+func pendingSlip(m *manager.Manager) (*timeslip.Slip, bool) {
+	data, err := m.PendingTimeSlip()
+	if err != nil {
+		return nil, false
+	}
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return nil, false
+	}
+
+	return slip, true
+}
+
+// Prints the stashed timeslips, most recent first, as they would be popped.
+func printStashedSlips(stashed [][]byte) {
+	if len(stashed) == 0 {
+		return
+	}
+
+	fmt.Println("Stashed:")
+	for i, data := range stashed {
+		slip := &timeslip.Slip{}
+		if err := timeslip.Unmarshal(data, slip); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("  %d: %s\n", i+1, slip)
+	}
+}
+
+// Loads the configuration, applying any global flags, and sets up the data
+// directory on a new install.
+func loadConfig() error {
+	var err error
+	if config, err = configuration.Load(); err != nil {
+		return err
+	}
+
+	if dataDirectory != "" {
+		if err := config.SetDataDirectory(dataDirectory); err != nil {
+			return err
+		}
+	}
+
+	if err := applyDisplayFormat(); err != nil {
+		return err
+	}
+	timeslip.DateFormat = config.DateFormat()
+	period.WeekStart = config.WeekStart()
+	period.FiscalYearStart = config.FiscalYearStart()
+
+	return setupNewInstall(config)
+}
+
+// Sets the format for displaying worked time, from the --duration-format
+// flag, otherwise the configuration.
+func applyDisplayFormat() error {
+	name := durationFormat
+	if name == "" {
+		name = initializeConfig().DurationFormat()
+	}
+
+	format, err := worked.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+	worked.DisplayFormat = format
+
+	return nil
+}
+
+// initializeConfig returns the configuration, as read from the config file
+// and ENV variables, falling back to the defaults if not yet loaded.
 func initializeConfig() *configuration.Config {
-	return configuration.New()
+	if config == nil {
+		config = configuration.New()
+	}
+	return config
 }
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/reports"
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+var (
+	searchIgnoreCase bool
+	searchPeriod     string
+	searchFrom       string
+	searchTo         string
+	searchFormat     string
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [flags] PATTERN [PROJECT]",
+	Short: "Search the timeslip descriptions",
+	Long: `Search the descriptions of all completed timeslips, listing those that match
+along with the total time worked on them.
+
+The PATTERN is a regular expression, e.g. 'login (bug|issue)'. Use -i for
+case-insensitive matching. A project name searches only its timeslips, and
+the --period, or --from/--to, flags limit the search to a time period, as
+with the report command.
+
+Examples:
+
+$ tw search -i 'login bug'
+=> All timeslips mentioning the login bug, and the total time spent on it.
+
+$ tw search -p 1m 'deploy' MyProject
+=> The MyProject deployments from last month.
+`,
+	Args:                  cobra.RangeArgs(1, 2),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectName := ""
+		if len(args) > 1 {
+			projectName = args[1]
+		}
+		if err := searchTimeslips(args[0], projectName); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	searchCmd.Flags().BoolVarP(&searchIgnoreCase, "ignore-case", "i", false, `case-insensitive matching.`)
+	searchCmd.Flags().StringVarP(&searchPeriod, "period", "p", "", `search timeslips for the time period, e.g. w, 1m, 'last 7 days'.`)
+	searchCmd.Flags().StringVar(&searchFrom, "from", "", `search timeslips from this date, e.g. 2026-09-01.`)
+	searchCmd.Flags().StringVar(&searchTo, "to", "", `search timeslips up to this date (default today).`)
+	searchCmd.Flags().StringVar(&searchFormat, "format", "text", `output format: text, json.`)
+
+	rootCmd.AddCommand(searchCmd)
+}
+
+func searchTimeslips(pattern, projectName string) error {
+	if searchIgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid search pattern: %v", err)
+	}
+
+	p, err := parsePeriod(searchPeriod, searchFrom, searchTo)
+	if err != nil {
+		return err
+	}
+	format, err := reports.ParseFormat(searchFormat)
+	if err != nil {
+		return err
+	}
+
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var from, to time.Time
+	if p.IsSet() {
+		from, to = p.From(), p.To()
+	}
+
+	var projects []string
+	if projectName == "" {
+		if projects, err = m.Projects(); err != nil {
+			return err
+		}
+	} else {
+		if !m.ProjectExists(projectName) {
+			return fmt.Errorf("project not found")
+		}
+		projects = []string{projectName}
+	}
+
+	log := reports.Log{ShowTotal: true, TimePeriod: p}
+
+	for _, project := range projects {
+		err := m.Slips(project, from, to, func(data []byte) error {
+			slip := &timeslip.Slip{}
+			if err := timeslip.Unmarshal(data, slip); err != nil {
+				return nil // unreadable timeslips are listed by the report command
+			}
+			if re.MatchString(slip.Description) {
+				log.Add(slip)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Sort(false, 0)
+
+	return log.Render(os.Stdout, format)
+}
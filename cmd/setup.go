@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mrcook/time_warrior/configuration"
+)
+
+// Creates the data directory and pending file on a new install. When the
+// user has opted into the XDG data directory, data from the legacy location
+// can be moved over, which is only offered until the new directory exists.
+func setupNewInstall(config *configuration.Config) error {
+	if config.VerifyDataFilesPresent() {
+		return nil
+	}
+
+	if err := migrateLegacyData(config); err != nil {
+		return err
+	}
+
+	dataFolder := config.DataDirectoryPath()
+	if _, err := os.Stat(dataFolder); os.IsNotExist(err) {
+		if err := os.MkdirAll(dataFolder, 0755); err != nil {
+			return err
+		}
+		fmt.Printf("data folder was created at %s\n", dataFolder)
+	}
+
+	pending := config.PendingFilePath()
+	if _, err := os.Stat(pending); err != nil {
+		f, createErr := os.Create(pending)
+		if createErr != nil {
+			return createErr
+		}
+		defer f.Close()
+		fmt.Println("pending file was created!")
+	}
+
+	if !config.VerifyDataFilesPresent() {
+		return fmt.Errorf("one or more data files are missing! Re-run the app")
+	}
+
+	return nil
+}
+
+// Offers to move the legacy data directory to the XDG data directory.
+func migrateLegacyData(config *configuration.Config) error {
+	legacy := config.LegacyDataDirectoryPath()
+	target := config.DataDirectoryPath()
+
+	if !config.UsesXDGDataDirectory() || legacy == target {
+		return nil
+	}
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	if info, err := os.Stat(legacy); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	fmt.Printf("Move your timeslips from %s to %s? [y/N] ", legacy, target)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Printf("Skipped, a new data folder will be used. Your timeslips remain in %s\n", legacy)
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(legacy, target); err != nil {
+		return fmt.Errorf("unable to move the data folder: %v", err)
+	}
+	fmt.Printf("data folder was moved to %s\n", target)
+
+	return nil
+}
@@ -5,22 +5,27 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/mrcook/time_warrior/manager"
 	"github.com/mrcook/time_warrior/timeslip"
 )
 
 var startCmd = &cobra.Command{
-	Use:   "start Project.Task",
+	Use:   "start [flags] Project.Task [+tag...]",
 	Short: "Start a new timeslip",
 	Long: `Start working on a new task, providing a project, and optional task name.
 
 Only alphanumeric characters are allowed - no spaces - the project and task
-name must be separated by a period. Example: MyProject.StartTask`,
+name must be separated by a period. Example: MyProject.StartTask
+
+Optional tags can be given after the name, each one prefixed with a plus.
+Example: MyProject.Standup +meeting +billable
+
+A forgotten start can be back-dated using --at or --ago.
+Example: tw start --ago 20m MyProject.StartTask`,
 	Aliases:               []string{"s"},
-	Args:                  cobra.ExactArgs(1),
+	Args:                  cobra.MinimumNArgs(1),
 	DisableFlagsInUseLine: true,
 	Run: func(cmd *cobra.Command, args []string) {
-		slip, err := startNewSlip(args[0])
+		slip, err := startNewSlip(args[0], args[1:])
 
 		if err != nil {
 			fmt.Println(err)
@@ -33,13 +38,28 @@ name must be separated by a period. Example: MyProject.StartTask`,
 }
 
 func init() {
+	addTimestampFlags(startCmd)
+
 	rootCmd.AddCommand(startCmd)
 }
 
-func startNewSlip(name string) (*timeslip.Slip, error) {
-	m := manager.NewFromConfig(initializeConfig())
+func startNewSlip(name string, tags []string) (*timeslip.Slip, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("start")
+	if err != nil {
+		return nil, err
+	}
 
-	if m.PendingTimeSlipExists() {
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
 		slipJSON, slipError := m.PendingTimeSlip()
 		if slipError == nil {
 			return nil, fmt.Errorf("pending timeslip already exists")
@@ -53,7 +73,17 @@ func startNewSlip(name string) (*timeslip.Slip, error) {
 		return slip, nil
 	}
 
-	slip, err := timeslip.New(name)
+	tags, err = timeslip.ParseTagArgs(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := transitionTime()
+	if err != nil {
+		return nil, err
+	}
+
+	slip, err := timeslip.NewAt(at, name, tags...)
 	if err != nil {
 		return nil, err
 	}
@@ -62,5 +92,9 @@ func startNewSlip(name string) (*timeslip.Slip, error) {
 		return nil, err
 	}
 
+	if err := m.Record(op, nil, nil); err != nil {
+		return slip, err
+	}
+
 	return slip, nil
 }
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/status"
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash [flags]",
+	Short: "Pause and stash the pending timeslip",
+	Long: `Pause the pending timeslip and put it to one side, so that a new timeslip
+can be started for an interruption. Use 'tw stash pop' to resume it again.
+
+Timeslips can be stashed more than once, and are popped in the reverse
+order. Run 'tw' to see the pending timeslip, followed by those stashed.
+
+A forgotten stash can be back-dated using --at or --ago, but not to
+before the timeslip was started or resumed.`,
+	Args:                  cobra.NoArgs,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		slip, err := stashTimeSlip()
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("Stashed: %s\n", slip)
+		}
+	},
+}
+
+var stashPopCmd = &cobra.Command{
+	Use:   "pop [flags]",
+	Short: "Resume the most recently stashed timeslip",
+	Long: `Resume the most recently stashed timeslip, making it the pending timeslip.
+Any pending timeslip must first be completed, or stashed.
+
+A forgotten pop can be back-dated using --at or --ago, but not to
+before the timeslip was paused.`,
+	Args:                  cobra.NoArgs,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		slip, err := popStashedTimeSlip()
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Println(slip)
+		}
+	},
+}
+
+func init() {
+	addTimestampFlags(stashCmd)
+	addTimestampFlags(stashPopCmd)
+
+	stashCmd.AddCommand(stashPopCmd)
+	rootCmd.AddCommand(stashCmd)
+}
+
+func stashTimeSlip() (*timeslip.Slip, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("stash")
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("no timeslip to stash")
+	}
+
+	slip, ok := pendingSlip(m)
+	if !ok {
+		return nil, fmt.Errorf("unable to read the pending timeslip")
+	}
+
+	at, err := transitionTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if slip.Status != status.Paused {
+		if err := slip.PauseAt(at); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.StashPending(slip.ToJson()); err != nil {
+		return nil, err
+	}
+
+	if err := m.Record(op, nil, nil); err != nil {
+		return slip, err
+	}
+
+	return slip, nil
+}
+
+func popStashedTimeSlip() (*timeslip.Slip, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("stash pop")
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("pending timeslip already exists, complete or stash it first")
+	}
+
+	stashed, err := m.StashedTimeSlips()
+	if err != nil {
+		return nil, err
+	}
+	if len(stashed) == 0 {
+		return nil, fmt.Errorf("no stashed timeslips found")
+	}
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(stashed[0], slip); err != nil {
+		return nil, err
+	}
+
+	at, err := transitionTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := slip.ResumeAt(at); err != nil {
+		return nil, err
+	}
+
+	if err := m.PopStash(slip.ToJson()); err != nil {
+		return nil, err
+	}
+
+	if err := m.Record(op, nil, nil); err != nil {
+		return slip, err
+	}
+
+	return slip, nil
+}
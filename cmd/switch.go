@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+var switchCmd = &cobra.Command{
+	Use:   "switch [flags] Project.Task [+tag...] 'Description'",
+	Short: "Complete the current timeslip and start a new one",
+	Long: `Mark the current timeslip as done, providing a useful description, and start
+working on a new task in one step. The new timeslip is started at the same
+time the current one is completed, so no time is lost between them.
+
+The project and task name, and any tags, are for the new timeslip, while
+the description is for the completed one.
+Example: tw switch MyProject.Standup +meeting 'Fixed the login bug'
+
+A forgotten switch can be back-dated using --at or --ago, but not to
+before the current timeslip was started or resumed.`,
+	Aliases:               []string{"sw"},
+	Args:                  cobra.MinimumNArgs(2),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		tags, description, err := timeslip.ParseTagDescriptionArgs(args[1:])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		completed, next, err := switchTimeSlip(args[0], tags, description)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		fmt.Println(completed)
+		fmt.Println(next)
+	},
+}
+
+func init() {
+	addTimestampFlags(switchCmd)
+
+	rootCmd.AddCommand(switchCmd)
+}
+
+func switchTimeSlip(name string, tags []string, description string) (*timeslip.Slip, *timeslip.Slip, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("switch")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, fmt.Errorf("no pending timeslip found")
+	}
+
+	slip, ok := pendingSlip(m)
+	if !ok {
+		return nil, nil, fmt.Errorf("unable to read the pending timeslip")
+	}
+
+	at, err := transitionTime()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next, err := timeslip.NewAt(at, name, tags...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := slip.DoneAt(at, description); err != nil {
+		return nil, nil, err
+	}
+
+	if err := m.SwitchPending(slip.Project, slip.ToJson(), next.ToJson()); err != nil {
+		return nil, nil, err
+	}
+
+	if err := m.Record(op, nil, slip.ToJson()); err != nil {
+		return slip, next, err
+	}
+
+	return slip, next, nil
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+var (
+	atTimestamp string
+	agoDuration string
+)
+
+// Accepted --at formats, a time only format uses the current date.
+var timestampFormats = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+const clockFormat = "15:04"
+
+// Adds the --at and --ago flags for back-dating a timeslip state change.
+func addTimestampFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&atTimestamp, "at", "", `use the given time, e.g. "09:30" or "2026-10-17 16:45"`)
+	cmd.Flags().StringVar(&agoDuration, "ago", "", `use a time from this duration ago, e.g. "20m"`)
+}
+
+// Returns the time to use for a state change, as given by the --at or --ago
+// flags, otherwise the current time.
+func transitionTime() (time.Time, error) {
+	now := time.Now()
+
+	switch {
+	case atTimestamp != "" && agoDuration != "":
+		return now, fmt.Errorf("only one of --at or --ago can be used")
+	case atTimestamp != "":
+		return parseTimestamp(atTimestamp, now)
+	case agoDuration != "":
+		w := worked.WorkTime{}
+		if err := w.FromString(agoDuration); err != nil {
+			return now, err
+		}
+		if w.ToSeconds() < 0 {
+			return now, fmt.Errorf("--ago duration must not be negative")
+		}
+		return now.Add(-time.Duration(w.ToSeconds()) * time.Second), nil
+	default:
+		return now, nil
+	}
+}
+
+func parseTimestamp(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation(clockFormat, value, now.Location()); err == nil {
+		year, month, day := now.Date()
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, now.Location()), nil
+	}
+
+	for _, format := range timestampFormats {
+		if t, err := time.ParseInLocation(format, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return now, fmt.Errorf("invalid time, expected '15:04' or '2006-01-02 15:04', got '%s'", value)
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/reports"
+	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/status"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore, or empty the deleted timeslips",
+	Long: `Timeslips removed with the delete command are moved to the trash, where they
+can be listed and restored, until the trash is emptied.`,
+	Args: cobra.NoArgs,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:                   "list",
+	Short:                 "List the deleted timeslips",
+	Args:                  cobra.NoArgs,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := listTrash(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore UUID",
+	Short: "Restore a deleted timeslip",
+	Long: `Restore a deleted timeslip, found by its UUID, or the start of it as shown by
+'tw trash list'. A completed timeslip is restored to its project, while the
+in progress timeslip is restored as pending, if there is no other pending
+timeslip.`,
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		slip, err := restoreTrashed(args[0])
+		if err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Println(slip)
+		}
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete all timeslips in the trash",
+	Long: `Permanently delete all timeslips in the trash.
+
+**Caution! This action can not be undone!**`,
+	Args:                  cobra.NoArgs,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := emptyTrash(); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Println("Trash emptied!")
+		}
+	},
+}
+
+func init() {
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+
+	rootCmd.AddCommand(trashCmd)
+}
+
+func listTrash() error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	trashed, err := m.TrashedTimeSlips()
+	if err != nil {
+		return err
+	}
+	if len(trashed) == 0 {
+		return fmt.Errorf("the trash is empty")
+	}
+
+	log := reports.Log{}
+	for _, t := range trashed {
+		slip := &timeslip.Slip{}
+		if err := timeslip.Unmarshal(t.Slip, slip); err != nil {
+			return err
+		}
+		log.Add(slip)
+	}
+
+	return log.Render(os.Stdout, reports.FormatText)
+}
+
+func restoreTrashed(uuid string) (*timeslip.Slip, error) {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	op, err := m.BeginOperation("trash restore")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.RestoreTrashed(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return nil, err
+	}
+
+	var restored []byte
+	if slip.Status == status.Completed {
+		restored = data
+	}
+	if err := m.Record(op, nil, restored); err != nil {
+		return slip, err
+	}
+
+	return slip, nil
+}
+
+func emptyTrash() error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.EmptyTrash()
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/mrcook/time_warrior/manager"
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+var undoList bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [flags]",
+	Short: "Undo the most recent change to the timeslips",
+	Long: `Undo the most recent change made to the timeslips by the start, pause, resume,
+adjust, done, delete, switch, stash, add, edit, or trash restore commands.
+Run it again to undo the change before that.
+
+Every change is recorded in the journal file of the data directory. Use
+--list to see the changes that can be undone, most recent first.
+
+A change can not be undone once the timeslip has been changed by hand, or
+by another command that has not been undone.`,
+	Args:                  cobra.NoArgs,
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		if undoList {
+			err = listUndoableOperations()
+		} else {
+			err = undoOperation()
+		}
+
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVarP(&undoList, "list", "l", false, `list the changes that can be undone.`)
+
+	rootCmd.AddCommand(undoCmd)
+}
+
+func undoOperation() error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	op, err := m.Undo()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Undone: %s\n", operationSummary(op))
+
+	if slip, ok := pendingSlip(m); ok {
+		fmt.Println(slip)
+	}
+
+	return nil
+}
+
+func listUndoableOperations() error {
+	m, unlock, err := lockManager()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	ops, err := m.UndoableOperations()
+	if err != nil {
+		return err
+	}
+	if len(ops) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+
+	for i := range ops {
+		fmt.Println(operationSummary(&ops[i]))
+	}
+
+	return nil
+}
+
+// Returns the time, name, and the timeslip changed by the operation.
+func operationSummary(op *manager.Operation) string {
+	at := time.Unix(int64(op.Time), 0).Format(timeslip.DateFormat)
+
+	name := ""
+	for _, c := range []*manager.Change{op.Completed, op.Pending} {
+		if c == nil {
+			continue
+		}
+		data := c.After
+		if len(data) == 0 {
+			data = c.Before
+		}
+		slip := &timeslip.Slip{}
+		if err := timeslip.Unmarshal(data, slip); err == nil {
+			name = slip.Name()
+			break
+		}
+	}
+
+	return fmt.Sprintf("%s  %-13s  %s", at, op.Name, name)
+}
@@ -1,21 +1,60 @@
-// Package configuration provides some default directory and file names.
+// Package configuration provides the application settings, and the default
+// directory and file names.
+//
+// Settings are read from a config file, either `$XDG_CONFIG_HOME/tw/config.toml`
+// or `~/.twrc`, and can be overridden with `TW_*` environment variables.
 package configuration
 
 import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/mitchellh/go-homedir"
 )
 
 // Config for the application files/folders
 type Config struct {
 	homeDirectory   string
-	dataFolder      string
+	dataDirectory   string
+	xdgDataDir      bool
+	configFile      string
 	pendingFilename string
+	lockFilename    string
+	trashFilename   string
+	journalFilename string
+	databaseName    string
+	storageBackend  string
+	durationFormat  string
+	dateFormat      string
+	weekStart       time.Weekday
+	fiscalYearStart time.Month
+	defaultPeriod   string
 }
 
+// settings are the values which can be given in the config file, or with
+// the TW_* environment variables.
+type settings struct {
+	DataDir        string `toml:"data_dir"`
+	XDGDataDir     bool   `toml:"xdg_data_dir"`
+	Storage        string `toml:"storage"`
+	DurationFormat string `toml:"duration_format"`
+	DateFormat     string `toml:"date_format"`
+	WeekStart      string `toml:"week_start"`
+	FiscalYear     string `toml:"fiscal_year_start"`
+	DefaultPeriod  string `toml:"default_period"`
+}
+
+const (
+	legacyDataFolder = "time_warrior"
+	xdgFolder        = "tw"
+	defaultFormat    = "2006-01-02 15:04"
+)
+
 // New returns a new configuration with some sane defaults
 func New() *Config {
 	home, err := homedir.Dir()
@@ -26,19 +65,135 @@ func New() *Config {
 
 	return &Config{
 		homeDirectory:   home,
-		dataFolder:      "time_warrior",
+		dataDirectory:   path.Join(home, legacyDataFolder),
 		pendingFilename: ".pending",
+		lockFilename:    ".lock",
+		trashFilename:   ".trash",
+		journalFilename: ".journal",
+		databaseName:    "time_warrior.db",
+		storageBackend:  "jsonl",
+		dateFormat:      defaultFormat,
+		weekStart:       time.Monday,
+		fiscalYearStart: time.January,
+	}
+}
+
+// Load returns the configuration from the config file, if one is found, and
+// the TW_* environment variables, with the defaults for any missing settings.
+func Load() (*Config, error) {
+	c := New()
+
+	s := settings{}
+	for _, filename := range c.configFiles() {
+		if _, err := os.Stat(filename); err != nil {
+			continue
+		}
+		if _, err := toml.DecodeFile(filename, &s); err != nil {
+			return nil, fmt.Errorf("unable to read config file %s: %v", filename, err)
+		}
+		c.configFile = filename
+		break
+	}
+
+	if err := s.fromEnvironment(); err != nil {
+		return nil, err
 	}
+
+	if err := c.apply(s); err != nil {
+		if c.configFile != "" {
+			return nil, fmt.Errorf("%v (config file: %s)", err, c.configFile)
+		}
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// SetDataDirectory overrides the data directory, e.g. from a CLI flag.
+func (c *Config) SetDataDirectory(dir string) error {
+	dir, err := homedir.Expand(dir)
+	if err != nil {
+		return err
+	}
+	c.dataDirectory = dir
+	return nil
+}
+
+// ConfigFilePath returns the config file that was loaded, if any.
+func (c Config) ConfigFilePath() string {
+	return c.configFile
 }
 
 func (c Config) DataDirectoryPath() string {
-	return path.Join(c.homeDirectory, c.dataFolder)
+	return c.dataDirectory
+}
+
+// LegacyDataDirectoryPath is the data directory used by older versions,
+// before the XDG data directory could be configured.
+func (c Config) LegacyDataDirectoryPath() string {
+	return path.Join(c.homeDirectory, legacyDataFolder)
+}
+
+// UsesXDGDataDirectory returns true if the user opted into using the
+// `$XDG_DATA_HOME/tw` data directory.
+func (c Config) UsesXDGDataDirectory() bool {
+	return c.xdgDataDir
 }
 
 func (c Config) PendingFilePath() string {
 	return path.Join(c.DataDirectoryPath(), c.pendingFilename)
 }
 
+func (c Config) LockFilePath() string {
+	return path.Join(c.DataDirectoryPath(), c.lockFilename)
+}
+
+// TrashFilePath is the file holding the deleted timeslips, until restored.
+func (c Config) TrashFilePath() string {
+	return path.Join(c.DataDirectoryPath(), c.trashFilename)
+}
+
+// JournalFilePath is the file recording each change made to the timeslips.
+func (c Config) JournalFilePath() string {
+	return path.Join(c.DataDirectoryPath(), c.journalFilename)
+}
+
+// DatabaseFilePath is the database file used by the "bolt" storage backend.
+func (c Config) DatabaseFilePath() string {
+	return path.Join(c.DataDirectoryPath(), c.databaseName)
+}
+
+// StorageBackend returns the name of the timeslip storage backend, either
+// "jsonl" (default) or "bolt".
+func (c Config) StorageBackend() string {
+	return c.storageBackend
+}
+
+// DurationFormat returns the name of the format for displaying worked time.
+func (c Config) DurationFormat() string {
+	return c.durationFormat
+}
+
+// DateFormat returns the Go time layout for displaying timestamps.
+func (c Config) DateFormat() string {
+	return c.dateFormat
+}
+
+// WeekStart returns the first day of the week, Monday by default.
+func (c Config) WeekStart() time.Weekday {
+	return c.weekStart
+}
+
+// FiscalYearStart returns the first month of the fiscal year, January by default.
+func (c Config) FiscalYearStart() time.Month {
+	return c.fiscalYearStart
+}
+
+// DefaultPeriod returns the report time period used when none is given.
+func (c Config) DefaultPeriod() string {
+	return c.defaultPeriod
+}
+
 func (c Config) VerifyDataFilesPresent() bool {
 	if _, err := os.Stat(c.DataDirectoryPath()); err != nil {
 		return false
@@ -50,3 +205,111 @@ func (c Config) VerifyDataFilesPresent() bool {
 
 	return true
 }
+
+// Returns the config file locations, in the order they are searched.
+func (c Config) configFiles() []string {
+	return []string{
+		path.Join(xdgDirectory("XDG_CONFIG_HOME", c.homeDirectory, ".config"), xdgFolder, "config.toml"),
+		path.Join(c.homeDirectory, ".twrc"),
+	}
+}
+
+// Applies the settings over the defaults.
+func (c *Config) apply(s settings) error {
+	c.xdgDataDir = s.XDGDataDir
+	if c.xdgDataDir {
+		c.dataDirectory = path.Join(xdgDirectory("XDG_DATA_HOME", c.homeDirectory, ".local/share"), xdgFolder)
+	}
+
+	if s.DataDir != "" {
+		if err := c.SetDataDirectory(s.DataDir); err != nil {
+			return err
+		}
+	}
+
+	if s.Storage != "" {
+		c.storageBackend = s.Storage
+	}
+	if s.DateFormat != "" {
+		c.dateFormat = s.DateFormat
+	}
+	c.durationFormat = s.DurationFormat
+	c.defaultPeriod = s.DefaultPeriod
+
+	if s.WeekStart != "" {
+		day, err := parseWeekday(s.WeekStart)
+		if err != nil {
+			return err
+		}
+		c.weekStart = day
+	}
+
+	if s.FiscalYear != "" {
+		month, err := parseMonth(s.FiscalYear)
+		if err != nil {
+			return err
+		}
+		c.fiscalYearStart = month
+	}
+
+	return nil
+}
+
+// Overrides the settings with any TW_* environment variables.
+func (s *settings) fromEnvironment() error {
+	values := map[string]*string{
+		"TW_DATA_DIR":          &s.DataDir,
+		"TW_STORAGE":           &s.Storage,
+		"TW_DURATION_FORMAT":   &s.DurationFormat,
+		"TW_DATE_FORMAT":       &s.DateFormat,
+		"TW_WEEK_START":        &s.WeekStart,
+		"TW_FISCAL_YEAR_START": &s.FiscalYear,
+		"TW_DEFAULT_PERIOD":    &s.DefaultPeriod,
+	}
+	for name, value := range values {
+		if v, ok := os.LookupEnv(name); ok {
+			*value = v
+		}
+	}
+
+	if v, ok := os.LookupEnv("TW_XDG_DATA_DIR"); ok {
+		xdg, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid TW_XDG_DATA_DIR value, expected true or false, got '%s'", v)
+		}
+		s.XDGDataDir = xdg
+	}
+
+	return nil
+}
+
+// Returns the XDG base directory from the environment variable, otherwise
+// the default directory within the home directory.
+func xdgDirectory(name, home, fallback string) string {
+	if dir := os.Getenv(name); dir != "" && path.IsAbs(dir) {
+		return dir
+	}
+	return path.Join(home, fallback)
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) || strings.EqualFold(day.String()[:3], name) {
+			return day, nil
+		}
+	}
+	return time.Monday, fmt.Errorf("invalid week_start day, got '%s'", name)
+}
+
+// Parses a month name, e.g. "april" or "apr", or number from 1 to 12.
+func parseMonth(name string) (time.Month, error) {
+	if n, err := strconv.Atoi(name); err == nil && n >= 1 && n <= 12 {
+		return time.Month(n), nil
+	}
+	for month := time.January; month <= time.December; month++ {
+		if strings.EqualFold(month.String(), name) || strings.EqualFold(month.String()[:3], name) {
+			return month, nil
+		}
+	}
+	return time.January, fmt.Errorf("invalid fiscal_year_start month, got '%s'", name)
+}
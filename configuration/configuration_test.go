@@ -0,0 +1,272 @@
+package configuration
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Sets a temporary home directory, clearing any XDG and TW_* variables, and
+// returns the home directory.
+func setupHome(t *testing.T) string {
+	home := t.TempDir()
+
+	homedir.DisableCache = true
+	t.Cleanup(func() { homedir.DisableCache = false })
+
+	t.Setenv("HOME", home)
+	for _, name := range []string{
+		"XDG_CONFIG_HOME", "XDG_DATA_HOME", "TW_DATA_DIR", "TW_XDG_DATA_DIR", "TW_STORAGE",
+		"TW_DURATION_FORMAT", "TW_DATE_FORMAT", "TW_WEEK_START", "TW_FISCAL_YEAR_START", "TW_DEFAULT_PERIOD",
+	} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+
+	return home
+}
+
+// Writes the config file, creating its directory.
+func writeConfig(t *testing.T, filename, data string) {
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad_ConfigFileOrder(t *testing.T) {
+	tests := map[string]struct {
+		xdg      bool
+		twrc     bool
+		expected string
+	}{
+		"no config file":      {false, false, ""},
+		"only the XDG config": {true, false, ".config/tw/config.toml"},
+		"only the twrc":       {false, true, ".twrc"},
+		"both config files":   {true, true, ".config/tw/config.toml"},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			home := setupHome(t)
+
+			if test.xdg {
+				writeConfig(t, path.Join(home, ".config/tw/config.toml"), `default_period = "xdg"`)
+			}
+			if test.twrc {
+				writeConfig(t, path.Join(home, ".twrc"), `default_period = "twrc"`)
+			}
+
+			c, err := Load()
+			if err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+
+			expected := test.expected
+			if expected != "" {
+				expected = path.Join(home, expected)
+			}
+			if c.ConfigFilePath() != expected {
+				t.Errorf("expected config file '%s', got '%s'", expected, c.ConfigFilePath())
+			}
+		})
+	}
+
+	t.Run("using XDG_CONFIG_HOME", func(t *testing.T) {
+		home := setupHome(t)
+		config := path.Join(home, "config")
+		t.Setenv("XDG_CONFIG_HOME", config)
+
+		writeConfig(t, path.Join(config, "tw/config.toml"), `storage = "bolt"`)
+		writeConfig(t, path.Join(home, ".twrc"), `storage = "jsonl"`)
+
+		c, err := Load()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if c.StorageBackend() != "bolt" {
+			t.Errorf("expected the XDG config to be used, got storage '%s'", c.StorageBackend())
+		}
+	})
+}
+
+func TestLoad_EnvironmentOverridesConfigFile(t *testing.T) {
+	home := setupHome(t)
+	writeConfig(t, path.Join(home, ".twrc"), `
+data_dir = "~/from_file"
+storage = "jsonl"
+duration_format = "decimal"
+date_format = "02/01/2006 15:04"
+week_start = "sunday"
+fiscal_year_start = "april"
+default_period = "week"
+`)
+
+	t.Setenv("TW_DATA_DIR", "~/from_env")
+	t.Setenv("TW_STORAGE", "bolt")
+	t.Setenv("TW_DURATION_FORMAT", "clock")
+	t.Setenv("TW_WEEK_START", "tue")
+	t.Setenv("TW_FISCAL_YEAR_START", "7")
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error, got '%s'", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"data directory", c.DataDirectoryPath(), path.Join(home, "from_env")},
+		{"storage", c.StorageBackend(), "bolt"},
+		{"duration format", c.DurationFormat(), "clock"},
+		{"week start", c.WeekStart(), time.Tuesday},
+		{"fiscal year start", c.FiscalYearStart(), time.July},
+		{"date format", c.DateFormat(), "02/01/2006 15:04"},
+		{"default period", c.DefaultPeriod(), "week"},
+	}
+	for _, test := range tests {
+		if test.got != test.expected {
+			t.Errorf("expected %s '%v', got '%v'", test.name, test.expected, test.got)
+		}
+	}
+}
+
+func TestLoad_InvalidValues(t *testing.T) {
+	tests := map[string]struct {
+		config   string
+		env      map[string]string
+		expected string
+	}{
+		"TW_XDG_DATA_DIR": {
+			env:      map[string]string{"TW_XDG_DATA_DIR": "maybe"},
+			expected: "invalid TW_XDG_DATA_DIR value, expected true or false, got 'maybe'",
+		},
+		"week_start": {
+			config:   `week_start = "someday"`,
+			expected: "invalid week_start day, got 'someday' (config file: ",
+		},
+		"fiscal_year_start": {
+			config:   `fiscal_year_start = "13"`,
+			expected: "invalid fiscal_year_start month, got '13' (config file: ",
+		},
+		"TW_WEEK_START": {
+			env:      map[string]string{"TW_WEEK_START": "funday"},
+			expected: "invalid week_start day, got 'funday'",
+		},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			home := setupHome(t)
+			if test.config != "" {
+				writeConfig(t, path.Join(home, ".twrc"), test.config)
+			}
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load()
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), test.expected) {
+				t.Errorf("unexpected error, got '%s'", err)
+			}
+		})
+	}
+}
+
+func TestLoad_DataDirectory(t *testing.T) {
+	tests := map[string]struct {
+		config      string
+		xdgDataHome string
+		expected    string
+	}{
+		"default":                         {"", "", "time_warrior"},
+		"xdg_data_dir with XDG_DATA_HOME": {`xdg_data_dir = true`, "/xdg/data", "/xdg/data/tw"},
+		"xdg_data_dir fallback":           {`xdg_data_dir = true`, "", ".local/share/tw"},
+		"relative XDG_DATA_HOME ignored":  {`xdg_data_dir = true`, "relative/data", ".local/share/tw"},
+		"data_dir over xdg_data_dir":      {"xdg_data_dir = true\ndata_dir = \"/custom/data\"", "/xdg/data", "/custom/data"},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			home := setupHome(t)
+			if test.config != "" {
+				writeConfig(t, path.Join(home, ".twrc"), test.config)
+			}
+			if test.xdgDataHome != "" {
+				t.Setenv("XDG_DATA_HOME", test.xdgDataHome)
+			}
+
+			c, err := Load()
+			if err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+
+			expected := test.expected
+			if !path.IsAbs(expected) {
+				expected = path.Join(home, expected)
+			}
+			if c.DataDirectoryPath() != expected {
+				t.Errorf("expected data directory '%s', got '%s'", expected, c.DataDirectoryPath())
+			}
+		})
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := map[string]time.Weekday{
+		"monday": time.Monday,
+		"Sunday": time.Sunday,
+		"SAT":    time.Saturday,
+		"wed":    time.Wednesday,
+	}
+
+	for name, expected := range tests {
+		day, err := parseWeekday(name)
+		if err != nil {
+			t.Errorf("unexpected error for '%s', got '%s'", name, err)
+		} else if day != expected {
+			t.Errorf("expected %s for '%s', got %s", expected, name, day)
+		}
+	}
+
+	for _, name := range []string{"", "mo", "weekday"} {
+		if _, err := parseWeekday(name); err == nil {
+			t.Errorf("expected an error for '%s'", name)
+		}
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	tests := map[string]time.Month{
+		"april": time.April,
+		"Oct":   time.October,
+		"1":     time.January,
+		"12":    time.December,
+	}
+
+	for name, expected := range tests {
+		month, err := parseMonth(name)
+		if err != nil {
+			t.Errorf("unexpected error for '%s', got '%s'", name, err)
+		} else if month != expected {
+			t.Errorf("expected %s for '%s', got %s", expected, name, month)
+		}
+	}
+
+	for _, name := range []string{"", "0", "13", "ap", "smarch"} {
+		if _, err := parseMonth(name); err == nil {
+			t.Errorf("expected an error for '%s'", name)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package manager
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+var (
+	pendingBucket  = []byte("pending")
+	projectsBucket = []byte("projects")
+	slipsBucket    = []byte("slips")
+	finishedBucket = []byte("finished")
+	pendingKey     = []byte("slip")
+	stashKey       = []byte("stash")
+	spanKey        = []byte("span")
+)
+
+// boltStore keeps all timeslips in a single embedded database file.
+//
+// Each project has its own bucket, holding the timeslips keyed by a sequence
+// number, along with an index of the finished times so that the timeslips
+// for a time period can be found without reading the whole project. The
+// longest time between the start and finish of any of its timeslips is also
+// kept, so a read of the index can stop once no later timeslip could have
+// been started within the time period.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(filename string) (*boltStore, error) {
+	db, err := bolt.Open(filename, 0644, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open database: %v", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(pendingBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(projectsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) PendingTimeSlip() ([]byte, error) {
+	var slip []byte
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		slip = append(slip, tx.Bucket(pendingBucket).Get(pendingKey)...)
+		return nil
+	})
+
+	return slip, err
+}
+
+func (s *boltStore) SavePending(slip []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingBucket).Put(pendingKey, slip)
+	})
+}
+
+func (s *boltStore) DeletePending() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingBucket).Delete(pendingKey)
+	})
+}
+
+func (s *boltStore) StashedTimeSlips() ([][]byte, error) {
+	var stashed [][]byte
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(pendingBucket).Get(stashKey)
+		if data == nil {
+			return nil
+		}
+
+		var slips []json.RawMessage
+		if err := json.Unmarshal(data, &slips); err != nil {
+			return fmt.Errorf("invalid stash data: %v", err)
+		}
+		for _, slip := range slips {
+			stashed = append(stashed, []byte(slip))
+		}
+		return nil
+	})
+
+	return stashed, err
+}
+
+func (s *boltStore) SavePendingStack(pending []byte, stashed [][]byte) error {
+	slips := make([]json.RawMessage, len(stashed))
+	for i, slip := range stashed {
+		slips[i] = json.RawMessage(slip)
+	}
+	data, err := json.Marshal(slips)
+	if err != nil {
+		return fmt.Errorf("invalid stash data: %v", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(pendingBucket)
+
+		if len(pending) == 0 {
+			if err := b.Delete(pendingKey); err != nil {
+				return err
+			}
+		} else if err := b.Put(pendingKey, pending); err != nil {
+			return err
+		}
+
+		if len(stashed) == 0 {
+			return b.Delete(stashKey)
+		}
+		return b.Put(stashKey, data)
+	})
+}
+
+func (s *boltStore) SaveCompleted(project string, slip []byte) error {
+	ts := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(slip, ts); err != nil {
+		return fmt.Errorf("invalid timeslip data: %v", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		p, err := tx.Bucket(projectsBucket).CreateBucketIfNotExists([]byte(toSnakeCase(project)))
+		if err != nil {
+			return err
+		}
+
+		slips, err := p.CreateBucketIfNotExists(slipsBucket)
+		if err != nil {
+			return err
+		}
+		finished, err := p.CreateBucketIfNotExists(finishedBucket)
+		if err != nil {
+			return err
+		}
+
+		seq, err := slips.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		if err := slips.Put(uint64Key(seq), slip); err != nil {
+			return err
+		}
+		if err := finished.Put(finishedKey(ts.Finished, seq), uint64Key(seq)); err != nil {
+			return err
+		}
+		return updateSpan(p, ts)
+	})
+}
+
+func (s *boltStore) ReplaceCompleted(project, uuid string, slip []byte) error {
+	replacement := &timeslip.Slip{}
+	if len(slip) > 0 {
+		if err := timeslip.Unmarshal(slip, replacement); err != nil {
+			return fmt.Errorf("invalid timeslip data: %v", err)
+		}
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		p := tx.Bucket(projectsBucket).Bucket([]byte(toSnakeCase(project)))
+		if p == nil {
+			return errSlipNotFound(uuid)
+		}
+		slips, finished := p.Bucket(slipsBucket), p.Bucket(finishedBucket)
+
+		c := slips.Cursor()
+		for k, data := c.First(); k != nil; k, data = c.Next() {
+			current := &timeslip.Slip{}
+			if err := timeslip.Unmarshal(data, current); err != nil || current.UUID != uuid {
+				continue
+			}
+			seq := binary.BigEndian.Uint64(k)
+
+			if err := finished.Delete(finishedKey(current.Finished, seq)); err != nil {
+				return err
+			}
+			if len(slip) == 0 {
+				return slips.Delete(k)
+			}
+
+			if err := slips.Put(k, slip); err != nil {
+				return err
+			}
+			if err := finished.Put(finishedKey(replacement.Finished, seq), uint64Key(seq)); err != nil {
+				return err
+			}
+			return updateSpan(p, replacement)
+		}
+
+		return errSlipNotFound(uuid)
+	})
+}
+
+func (s *boltStore) Projects() ([]string, error) {
+	var projects []string
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(projectsBucket).ForEachBucket(func(name []byte) error {
+			projects = append(projects, string(name))
+			return nil
+		})
+	})
+
+	return projects, err
+}
+
+func (s *boltStore) Slips(project string, from, to time.Time, fn func(slip []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		p := tx.Bucket(projectsBucket).Bucket([]byte(toSnakeCase(project)))
+		if p == nil {
+			return nil
+		}
+		slips := p.Bucket(slipsBucket)
+
+		start := finishedKey(0, 0)
+		if !from.IsZero() {
+			start = finishedKey(int(from.Unix()), 0)
+		}
+
+		// timeslips finished later than this were started after the time period
+		last, bounded := 0, false
+		if span := p.Get(spanKey); span != nil && !to.IsZero() {
+			last, bounded = int(to.Unix())+int(binary.BigEndian.Uint64(span)), true
+		}
+
+		c := p.Bucket(finishedBucket).Cursor()
+		for k, seq := c.Seek(start); k != nil; k, seq = c.Next() {
+			if bounded && int(binary.BigEndian.Uint64(k)) > last {
+				break
+			}
+
+			slip := slips.Get(seq)
+			if slip == nil || !withinTimeRange(slip, from, to) {
+				continue
+			}
+			if err := fn(slip); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+// Saves the time between the start and finish of the timeslip as the project
+// span, when it is longer than any other.
+func updateSpan(p *bolt.Bucket, slip *timeslip.Slip) error {
+	span := slip.Finished - slip.Started
+	if span < 0 {
+		span = 0
+	}
+	if current := p.Get(spanKey); current != nil && binary.BigEndian.Uint64(current) >= uint64(span) {
+		return nil
+	}
+	return p.Put(spanKey, uint64Key(uint64(span)))
+}
+
+func uint64Key(n uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, n)
+	return key
+}
+
+// Index keys sort by the finished time, with the sequence keeping them unique.
+func finishedKey(finished int, seq uint64) []byte {
+	if finished < 0 {
+		finished = 0
+	}
+	return append(uint64Key(uint64(finished)), uint64Key(seq)...)
+}
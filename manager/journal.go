@@ -0,0 +1,540 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+// Change is the JSON data of a timeslip, or of the stashed timeslips, before
+// and after an operation. Either is empty when there was no timeslip.
+type Change struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Operation is an entry of the journal, recording the changes made by a
+// command so that they can be undone. An undo is itself recorded, as an
+// "undo" operation with the ID of the operation it reverted.
+//
+// The trash change holds JSON arrays of the trash entries removed (before)
+// and added (after) by the operation, rather than the whole trash.
+type Operation struct {
+	ID        int     `json:"id"`
+	Time      int     `json:"time"`
+	Name      string  `json:"operation"`
+	Pending   *Change `json:"pending,omitempty"`
+	Stashed   *Change `json:"stashed,omitempty"`
+	Completed *Change `json:"completed,omitempty"`
+	Trash     *Change `json:"trash,omitempty"`
+	Undo      int     `json:"undo,omitempty"`
+
+	trashed [][]byte // trash entries before the operation
+}
+
+const undoOperation = "undo"
+
+// BeginOperation returns a new operation for the journal, holding the state
+// of the pending and stashed timeslips before any changes are made.
+func (m Manager) BeginOperation(name string) (*Operation, error) {
+	pending, stashed, err := m.pendingState()
+	if err != nil {
+		return nil, err
+	}
+
+	trashed, err := m.trashState()
+	if err != nil {
+		return nil, err
+	}
+
+	op := &Operation{
+		Name:    name,
+		Pending: &Change{Before: pending},
+		Stashed: &Change{Before: stashed},
+		trashed: trashed,
+	}
+
+	return op, nil
+}
+
+// Record appends the operation to the journal, once the changes have been
+// made, along with the completed timeslip before and after any change to it.
+// Nothing is recorded when the operation made no changes.
+func (m Manager) Record(op *Operation, completedBefore, completedAfter []byte) error {
+	pending, stashed, err := m.pendingState()
+	if err != nil {
+		return err
+	}
+
+	op.Pending.After = pending
+	op.Stashed.After = stashed
+	op.Completed = &Change{Before: compactJSON(completedBefore), After: compactJSON(completedAfter)}
+
+	if sameJSON(op.Pending.Before, op.Pending.After) {
+		op.Pending = nil
+	}
+	if sameJSON(op.Stashed.Before, op.Stashed.After) {
+		op.Stashed = nil
+	}
+	if sameJSON(op.Completed.Before, op.Completed.After) {
+		op.Completed = nil
+	}
+
+	trashed, err := m.trashState()
+	if err != nil {
+		return err
+	}
+	removed, added := missingFrom(op.trashed, trashed), missingFrom(trashed, op.trashed)
+	if len(removed) > 0 || len(added) > 0 {
+		if op.Trash, err = trashChange(removed, added); err != nil {
+			return err
+		}
+	}
+
+	if op.Pending == nil && op.Stashed == nil && op.Completed == nil && op.Trash == nil {
+		return nil
+	}
+
+	return m.appendToJournal(op)
+}
+
+// Journal returns all the operations recorded in the journal, oldest first.
+func (m Manager) Journal() ([]Operation, error) {
+	file, err := os.Open(m.files.Journal)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var ops []Operation
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		op := Operation{}
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			return nil, fmt.Errorf("invalid journal data: %v", err)
+		}
+		ops = append(ops, op)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read journal file: %v", err)
+	}
+
+	return ops, nil
+}
+
+// UndoableOperations returns the operations which have not been undone,
+// most recent first, which is the order they can be undone in.
+func (m Manager) UndoableOperations() ([]Operation, error) {
+	ops, err := m.Journal()
+	if err != nil {
+		return nil, err
+	}
+
+	undone := make(map[int]bool)
+	for _, op := range ops {
+		if op.Name == undoOperation {
+			undone[op.Undo] = true
+		}
+	}
+
+	var undoable []Operation
+	for i := len(ops) - 1; i >= 0; i-- {
+		if ops[i].Name != undoOperation && !undone[ops[i].ID] {
+			undoable = append(undoable, ops[i])
+		}
+	}
+
+	return undoable, nil
+}
+
+// Undo reverts the most recent operation not already undone, returning it.
+// An operation can only be undone while the timeslips it changed are
+// unchanged since, which is always the case when undoing them in order.
+func (m Manager) Undo() (*Operation, error) {
+	undoable, err := m.UndoableOperations()
+	if err != nil {
+		return nil, err
+	}
+	if len(undoable) == 0 {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+	op := undoable[0]
+
+	if err := m.revert(op); err != nil {
+		return nil, err
+	}
+
+	undo := &Operation{Name: undoOperation, Undo: op.ID}
+	if err := m.appendToJournal(undo); err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// Reverts the changes made by the operation. A change already reverted, as
+// by an undo that was interrupted, is skipped.
+func (m Manager) revert(op Operation) error {
+	errChanged := fmt.Errorf("timeslips have been changed since the '%s' operation, it can not be undone", op.Name)
+
+	pending, stashed, err := m.pendingState()
+	if err != nil {
+		return err
+	}
+	if !atChange(op.Pending, pending) || !atChange(op.Stashed, stashed) {
+		return errChanged
+	}
+
+	var project, uuid string
+	var completed []byte
+	if op.Completed != nil {
+		slip := &timeslip.Slip{}
+		data := op.Completed.After
+		if len(data) == 0 {
+			data = op.Completed.Before
+		}
+		if err := timeslip.Unmarshal(data, slip); err != nil {
+			return fmt.Errorf("invalid journal data: %v", err)
+		}
+		project, uuid = slip.Project, slip.UUID
+
+		if completed, err = m.findByUUID(project, uuid); err != nil {
+			return err
+		}
+		if !atChange(op.Completed, completed) {
+			return errChanged
+		}
+	}
+
+	var trashed, removed, added [][]byte
+	if op.Trash != nil {
+		if trashed, err = m.trashState(); err != nil {
+			return err
+		}
+		if removed, err = jsonArray(op.Trash.Before); err != nil {
+			return err
+		}
+		if added, err = jsonArray(op.Trash.After); err != nil {
+			return err
+		}
+
+		atAfter := len(missingFrom(added, trashed)) == 0 && len(missingFrom(removed, trashed)) == len(removed)
+		atBefore := len(missingFrom(added, trashed)) == len(added) && len(missingFrom(removed, trashed)) == 0
+		if !atAfter && !atBefore {
+			return errChanged
+		}
+	}
+
+	if op.Pending != nil || op.Stashed != nil {
+		if op.Pending != nil {
+			pending = op.Pending.Before
+		}
+		if op.Stashed != nil {
+			stashed = op.Stashed.Before
+		}
+
+		slips, err := jsonArray(stashed)
+		if err != nil {
+			return err
+		}
+
+		if err := m.store.SavePendingStack(pending, slips); err != nil {
+			return fmt.Errorf("unable to save pending timeslip: %v", err)
+		}
+	}
+
+	if op.Completed != nil && !sameJSON(completed, op.Completed.Before) {
+		switch {
+		case len(op.Completed.Before) == 0:
+			err = m.store.ReplaceCompleted(project, uuid, nil)
+		case len(completed) == 0:
+			err = m.store.SaveCompleted(project, op.Completed.Before)
+		default:
+			err = m.store.ReplaceCompleted(project, uuid, op.Completed.Before)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to save completed timeslip: %v", err)
+		}
+	}
+
+	if op.Trash != nil {
+		var entries []TrashedSlip
+		for _, data := range append(missingFrom(trashed, added), missingFrom(removed, trashed)...) {
+			t := TrashedSlip{}
+			if err := json.Unmarshal(data, &t); err != nil {
+				return fmt.Errorf("invalid journal data: %v", err)
+			}
+			entries = append(entries, t)
+		}
+		if err := m.saveTrash(entries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the pending timeslip, and the stashed timeslips as a JSON array,
+// with either being empty when there are none.
+func (m Manager) pendingState() ([]byte, []byte, error) {
+	pending, err := m.store.PendingTimeSlip()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stashed, err := m.StashedTimeSlips()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stashed) == 0 {
+		return compactJSON(pending), nil, nil
+	}
+
+	slips := make([]json.RawMessage, len(stashed))
+	for i, slip := range stashed {
+		slips[i] = json.RawMessage(slip)
+	}
+	data, err := json.Marshal(slips)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid stash data: %v", err)
+	}
+
+	return compactJSON(pending), data, nil
+}
+
+// Returns the JSON data of each trash entry.
+func (m Manager) trashState() ([][]byte, error) {
+	trashed, err := m.TrashedTimeSlips()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries [][]byte
+	for _, t := range trashed {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash data: %v", err)
+		}
+		entries = append(entries, data)
+	}
+
+	return entries, nil
+}
+
+// Returns the trash change for the entries removed and added, as JSON arrays.
+func trashChange(removed, added [][]byte) (*Change, error) {
+	c := &Change{}
+
+	for _, entries := range []struct {
+		data   [][]byte
+		target *json.RawMessage
+	}{{removed, &c.Before}, {added, &c.After}} {
+		if len(entries.data) == 0 {
+			continue
+		}
+		raw := make([]json.RawMessage, len(entries.data))
+		for i, e := range entries.data {
+			raw[i] = e
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trash data: %v", err)
+		}
+		*entries.target = data
+	}
+
+	return c, nil
+}
+
+// Returns each element of the JSON array, which may be empty.
+func jsonArray(data []byte) ([][]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid journal data: %v", err)
+	}
+
+	elements := make([][]byte, len(raw))
+	for i, e := range raw {
+		elements[i] = e
+	}
+	return elements, nil
+}
+
+// Returns the JSON values of a which are not in b.
+func missingFrom(a, b [][]byte) [][]byte {
+	var missing [][]byte
+
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if sameJSON(x, y) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, x)
+		}
+	}
+
+	return missing
+}
+
+// Returns the completed timeslip of the project with the UUID, which is empty
+// when there is none.
+func (m Manager) findByUUID(project, uuid string) ([]byte, error) {
+	var found []byte
+
+	err := m.store.Slips(project, time.Time{}, time.Time{}, func(data []byte) error {
+		if slipUUID(data) == uuid {
+			found = append([]byte{}, data...)
+		}
+		return nil
+	})
+
+	return found, err
+}
+
+// Appends the operation to the journal file, numbering it after the last.
+// Only the last operation is read, to find its ID, and once the journal grows
+// past maxJournalSize the oldest operations are pruned.
+func (m Manager) appendToJournal(op *Operation) error {
+	file, err := os.OpenFile(m.files.Journal, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return fmt.Errorf("unable to open journal file: %v", err)
+	}
+	defer file.Close()
+
+	last, err := lastLine(file)
+	if err != nil {
+		return fmt.Errorf("unable to read journal file: %v", err)
+	}
+
+	op.ID = 1
+	if len(last) > 0 {
+		previous := Operation{}
+		if err := json.Unmarshal(last, &previous); err != nil {
+			return fmt.Errorf("invalid journal data: %v", err)
+		}
+		op.ID = previous.ID + 1
+	}
+	op.Time = int(time.Now().Unix())
+
+	data, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("invalid journal data: %v", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("unable to save to journal file: %v", err)
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > maxJournalSize {
+		return m.pruneJournal()
+	}
+
+	return nil
+}
+
+// The journal is pruned back to half this size, in bytes, once it is larger.
+var maxJournalSize int64 = 1 << 20
+
+// Removes the oldest operations from the journal, keeping the most recent
+// half of maxJournalSize, which can then still be undone.
+func (m Manager) pruneJournal() error {
+	data, err := os.ReadFile(m.files.Journal)
+	if err != nil {
+		return err
+	}
+
+	cut := int64(len(data)) - maxJournalSize/2
+	if cut <= 0 {
+		return nil
+	}
+	if i := bytes.IndexByte(data[cut-1:], '\n'); i >= 0 {
+		data = data[cut+int64(i):]
+	} else {
+		data = nil
+	}
+
+	if err := writeFileAtomic(m.files.Journal, data); err != nil {
+		return fmt.Errorf("unable to prune journal file: %v", err)
+	}
+	return nil
+}
+
+// Returns the last line of the file, reading back from the end in blocks.
+func lastLine(file *os.File) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	block := make([]byte, 4096)
+
+	for offset := info.Size(); offset > 0; {
+		n := min(int64(len(block)), offset)
+		offset -= n
+		if _, err := file.ReadAt(block[:n], offset); err != nil {
+			return nil, err
+		}
+		data = append(append([]byte{}, block[:n]...), data...)
+
+		line := bytes.TrimRight(data, "\r\n")
+		if i := bytes.LastIndexByte(line, '\n'); i >= 0 {
+			return line[i+1:], nil
+		}
+	}
+
+	return bytes.TrimSpace(data), nil
+}
+
+// Returns true if the data matches the state either before or after the
+// change, or when there was no change.
+func atChange(c *Change, data []byte) bool {
+	return c == nil || sameJSON(data, c.After) || sameJSON(data, c.Before)
+}
+
+// Returns true if the JSON data is the same, ignoring any whitespace.
+func sameJSON(a, b []byte) bool {
+	return bytes.Equal(compactJSON(a), compactJSON(b))
+}
+
+// Returns the JSON data without whitespace, or as given when not valid JSON.
+func compactJSON(data []byte) []byte {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,223 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jsonlStore is the default storage backend. The pending timeslip is kept in
+// its own file, with the completed timeslips for each project saved to a JSON
+// file, one per line, named after the project.
+//
+// The first line of the pending file is the pending timeslip, which is blank
+// when there is none, followed by any stashed timeslips, most recent first.
+type jsonlStore struct {
+	dataDirectory string
+	pendingFile   string
+}
+
+func newJSONLStore(dataDirectory, pendingFile string) *jsonlStore {
+	return &jsonlStore{
+		dataDirectory: dataDirectory,
+		pendingFile:   pendingFile,
+	}
+}
+
+func (s *jsonlStore) PendingTimeSlip() ([]byte, error) {
+	pending, _, err := s.readPendingStack()
+	return pending, err
+}
+
+func (s *jsonlStore) SavePending(slip []byte) error {
+	_, stashed, err := s.readPendingStack()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.SavePendingStack(slip, stashed)
+}
+
+func (s *jsonlStore) DeletePending() error {
+	return s.SavePending([]byte{})
+}
+
+func (s *jsonlStore) StashedTimeSlips() ([][]byte, error) {
+	_, stashed, err := s.readPendingStack()
+	return stashed, err
+}
+
+func (s *jsonlStore) SavePendingStack(pending []byte, stashed [][]byte) error {
+	data := bytes.TrimSpace(pending)
+	if len(stashed) > 0 {
+		data = append(append([]byte{}, data...), '\n')
+		for _, slip := range stashed {
+			data = append(append(data, bytes.TrimSpace(slip)...), '\n')
+		}
+	}
+	return writeFileAtomic(s.pendingFile, data)
+}
+
+// Reads the pending timeslip, and the stashed timeslips that follow it.
+func (s *jsonlStore) readPendingStack() ([]byte, [][]byte, error) {
+	data, err := os.ReadFile(s.pendingFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	pending := bytes.TrimSpace(lines[0])
+
+	var stashed [][]byte
+	for _, line := range lines[1:] {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			stashed = append(stashed, line)
+		}
+	}
+
+	return pending, stashed, nil
+}
+
+func (s *jsonlStore) SaveCompleted(project string, slip []byte) error {
+	slip = append(slip[:], []byte("\n")...)
+
+	file, err := os.OpenFile(s.projectFilename(project), os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := file.Write(slip); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+func (s *jsonlStore) ReplaceCompleted(project, uuid string, slip []byte) error {
+	filename := s.projectFilename(project)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return errSlipNotFound(uuid)
+	} else if err != nil {
+		return err
+	}
+
+	var lines [][]byte
+	found := false
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		if !found && slipUUID(line) == uuid {
+			found = true
+			if len(slip) == 0 {
+				continue
+			}
+			line = slip
+		}
+		lines = append(lines, line)
+	}
+
+	if !found {
+		return errSlipNotFound(uuid)
+	}
+
+	data = bytes.Join(lines, []byte("\n"))
+	if len(data) > 0 {
+		data = append(data, '\n')
+	}
+
+	return writeFileAtomic(filename, data)
+}
+
+func (s *jsonlStore) Projects() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.dataDirectory, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, f := range files {
+		projects = append(projects, strings.TrimSuffix(filepath.Base(f), ".json"))
+	}
+	sort.Strings(projects)
+
+	return projects, nil
+}
+
+func (s *jsonlStore) Slips(project string, from, to time.Time, fn func(slip []byte) error) error {
+	file, err := os.Open(s.projectFilename(project))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 || !withinTimeRange(scanner.Bytes(), from, to) {
+			continue
+		}
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("unable to read project file: %v", err)
+	}
+
+	return nil
+}
+
+func (s *jsonlStore) Close() error {
+	return nil
+}
+
+func (s *jsonlStore) projectFilename(project string) string {
+	return filepath.Join(s.dataDirectory, toSnakeCase(project)+".json")
+}
+
+// Writes the data to a temporary file which then replaces the named file, so
+// that it is never left partially written. The file keeps its permissions,
+// or is given the usual 0644 when new.
+func writeFileAtomic(filename string, data []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if err := temp.Chmod(mode); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+
+	if err := temp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(temp.Name(), filename)
+}
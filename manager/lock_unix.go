@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+package manager
+
+import (
+	"os"
+	"syscall"
+)
+
+// Takes an exclusive advisory lock on the lock file, without blocking.
+// A nil file is returned when the lock is held by another process.
+func acquireLock(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		file.Close()
+		return nil, nil
+	} else if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Releases the lock, which is also released by the OS if the process exits.
+func releaseLock(file *os.File) error {
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_UN); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+package manager
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Takes an exclusive lock on the first byte of the lock file, without
+// blocking. A nil file is returned when the lock is held by another process.
+// The lock is released by Windows if the process exits, so a lock file left
+// behind by a crash does not keep the data directory locked.
+func acquireLock(filename string) (*os.File, error) {
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := uint32(windows.LOCKFILE_EXCLUSIVE_LOCK | windows.LOCKFILE_FAIL_IMMEDIATELY)
+	err = windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, &windows.Overlapped{})
+	if err == windows.ERROR_LOCK_VIOLATION {
+		file.Close()
+		return nil, nil
+	} else if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Releases the lock, which is also released by Windows if the process exits.
+func releaseLock(file *os.File) error {
+	if err := windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{}); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
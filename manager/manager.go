@@ -1,115 +1,428 @@
-// Package manager provides timeslip file management.
+// Package manager provides timeslip storage management.
 package manager
 
 import (
+	"bytes"
 	"fmt"
-	"os"
-	"path"
-	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/mrcook/time_warrior/configuration"
+	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/status"
+)
+
+// How long to wait for another process to release the data directory lock.
+const (
+	lockTimeout    = 5 * time.Second
+	lockRetryDelay = 50 * time.Millisecond
 )
 
 type Manager struct {
-	dataDirectory string
-	pendingFile   string
+	store Store
+	files Files
+}
+
+// Files are the paths of the files kept alongside the timeslip store.
+type Files struct {
+	Lock    string // lock file held while making changes
+	Trash   string // deleted timeslips, until restored
+	Journal string // changes made to the timeslips, for undo
+}
+
+// NewFromConfig returns a new manager from a config, using the configured
+// storage backend.
+func NewFromConfig(cfg *configuration.Config) (*Manager, error) {
+	store, err := newStore(cfg, cfg.StorageBackend())
+	if err != nil {
+		return nil, err
+	}
+
+	files := Files{
+		Lock:    cfg.LockFilePath(),
+		Trash:   cfg.TrashFilePath(),
+		Journal: cfg.JournalFilePath(),
+	}
+
+	return New(store, files), nil
 }
 
-// NewFromConfig returns a new manager from a config.
-func NewFromConfig(cfg *configuration.Config) *Manager {
+// Returns the storage backend for the data directory of the config.
+func newStore(cfg *configuration.Config, backend string) (Store, error) {
+	switch backend {
+	case JSONLStorage:
+		return newJSONLStore(cfg.DataDirectoryPath(), cfg.PendingFilePath()), nil
+	case BoltStorage:
+		s, err := newBoltStore(cfg.DatabaseFilePath())
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend, got '%s'", backend)
+	}
+}
+
+// New returns a new manager for the store, using the given files.
+func New(store Store, files Files) *Manager {
 	return &Manager{
-		dataDirectory: cfg.DataDirectoryPath(),
-		pendingFile:   cfg.PendingFilePath(),
+		store: store,
+		files: files,
+	}
+}
+
+// Close closes the storage backend.
+func (m Manager) Close() error {
+	return m.store.Close()
+}
+
+// Lock takes an advisory lock on the data directory, so that only one process
+// can change the timeslips at a time. The returned function releases the lock.
+func (m Manager) Lock() (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+
+	for {
+		file, err := acquireLock(m.files.Lock)
+		if err != nil {
+			return nil, fmt.Errorf("unable to lock the data directory: %v", err)
+		}
+
+		if file != nil {
+			return func() { _ = releaseLock(file) }, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("data directory is locked by another process, lock file: %s", m.files.Lock)
+		}
+		time.Sleep(lockRetryDelay)
 	}
 }
 
-// PendingTimeSlip reads a timeslip from the pending file.
+// PendingTimeSlip reads the pending timeslip.
 func (m Manager) PendingTimeSlip() ([]byte, error) {
-	if !m.PendingTimeSlipExists() {
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
 		return nil, fmt.Errorf("can not resume, no pending timeslip found")
 	}
 
-	slip, err := os.ReadFile(m.pendingFile)
+	slip, err := m.store.PendingTimeSlip()
 	if err != nil {
 		return nil, err
 	}
 	return slip, nil
 }
 
-// PendingTimeSlipExists returns true if the pending file contains a current timeslip.
-func (m Manager) PendingTimeSlipExists() bool {
-	file, err := os.Stat(m.pendingFile)
+// PendingTimeSlipExists returns true if there is a current pending timeslip.
+func (m Manager) PendingTimeSlipExists() (bool, error) {
+	slip, err := m.store.PendingTimeSlip()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		return false, err
 	}
 
-	if file.Size() > 0 {
-		return true
-	}
-	return false
+	return len(slip) > 0, nil
 }
 
-// SaveCompleted saves a timeslip to the project JSON file.
+// SaveCompleted saves a completed timeslip to its project.
 func (m Manager) SaveCompleted(project string, slip []byte) error {
-	slip = append(slip[:], []byte("\n")...)
+	if err := m.store.SaveCompleted(project, slip); err != nil {
+		return fmt.Errorf("unable to save completed timeslip: %v", err)
+	}
+	return nil
+}
+
+// SavePending saves a timeslip as the pending timeslip.
+func (m Manager) SavePending(slip []byte) error {
+	if len(slip) == 0 {
+		return fmt.Errorf("missing pending JSON data")
+	}
 
-	filename := path.Join(m.dataDirectory, toSnakeCase(project)+".json")
+	if err := m.store.SavePending(slip); err != nil {
+		return fmt.Errorf("unable to save pending timeslip: %v", err)
+	}
+
+	return nil
+}
+
+// DeletePending deletes any pending timeslip.
+func (m Manager) DeletePending() error {
+	if err := m.store.DeletePending(); err != nil {
+		return fmt.Errorf("pending timeslip may not have been deleted")
+	}
+	return nil
+}
 
-	file, err := os.OpenFile(filename, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+// StashedTimeSlips returns the stashed timeslips, most recent first.
+func (m Manager) StashedTimeSlips() ([][]byte, error) {
+	stashed, err := m.store.StashedTimeSlips()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("unable to read stashed timeslips: %v", err)
+	}
+	return stashed, nil
+}
+
+// StashPending pushes the pending timeslip on to the stash, leaving no
+// pending timeslip, so that an interruption can be started. The timeslip is
+// saved as given, which is normally after being paused.
+func (m Manager) StashPending(slip []byte) error {
+	if len(slip) == 0 {
+		return fmt.Errorf("missing pending JSON data")
 	}
-	defer file.Close()
 
-	_, err = file.Write(slip)
+	stashed, err := m.StashedTimeSlips()
 	if err != nil {
-		return fmt.Errorf("unable to save completed timeslip: %v", err)
+		return err
+	}
+
+	stashed = append([][]byte{slip}, stashed...)
+	if err := m.store.SavePendingStack(nil, stashed); err != nil {
+		return fmt.Errorf("unable to stash pending timeslip: %v", err)
 	}
 
 	return nil
 }
 
-// SavePending saves a timeslip to the pending file.
-func (m Manager) SavePending(slip []byte) error {
+// PopStash removes the most recently stashed timeslip, saving the given
+// timeslip data as the pending timeslip in its place, which is normally the
+// stashed timeslip after being resumed. It returns an error if a pending
+// timeslip already exists.
+func (m Manager) PopStash(slip []byte) error {
 	if len(slip) == 0 {
 		return fmt.Errorf("missing pending JSON data")
 	}
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("pending timeslip already exists")
+	}
 
-	err := os.WriteFile(m.pendingFile, slip, 0644)
+	stashed, err := m.StashedTimeSlips()
 	if err != nil {
-		return fmt.Errorf("unable to save pending timeslip: %v", err)
+		return err
+	}
+	if len(stashed) == 0 {
+		return fmt.Errorf("no stashed timeslips found")
+	}
+
+	if err := m.store.SavePendingStack(slip, stashed[1:]); err != nil {
+		return fmt.Errorf("unable to restore stashed timeslip: %v", err)
 	}
 
 	return nil
 }
 
-// DeletePending deletes any timeslip found in the pending file.
-func (m Manager) DeletePending() error {
-	if err := os.Truncate(m.pendingFile, 0); err != nil {
-		return fmt.Errorf("pending timeslip may not have been deleted")
+// CompletePending moves a completed timeslip from pending to its project.
+// The completed timeslip is first saved as pending, so that if the process
+// is interrupted, Recover can finish the move.
+func (m Manager) CompletePending(project string, slip []byte) error {
+	if err := m.SavePending(slip); err != nil {
+		return err
+	}
+
+	if err := m.SaveCompleted(project, slip); err != nil {
+		return err
+	}
+
+	return m.DeletePending()
+}
+
+// SwitchPending completes the pending timeslip, moving it to its project,
+// and saves the next timeslip as pending in its place. The next timeslip is
+// first saved at the top of the stash, with the completed timeslip as pending,
+// so that if the process is interrupted, Recover can finish the switch.
+func (m Manager) SwitchPending(project string, completed, next []byte) error {
+	if len(completed) == 0 || len(next) == 0 {
+		return fmt.Errorf("missing pending JSON data")
+	}
+
+	stashed, err := m.StashedTimeSlips()
+	if err != nil {
+		return err
+	}
+
+	if err := m.store.SavePendingStack(completed, append([][]byte{next}, stashed...)); err != nil {
+		return fmt.Errorf("unable to save pending timeslip: %v", err)
+	}
+
+	if err := m.SaveCompleted(project, completed); err != nil {
+		return err
+	}
+
+	if err := m.store.SavePendingStack(next, stashed); err != nil {
+		return fmt.Errorf("unable to save pending timeslip: %v", err)
 	}
+
 	return nil
 }
 
-// AllProjectFilenames return a list of file names for all projects.
-func (m Manager) AllProjectFilenames() []string {
-	files, _ := filepath.Glob(filepath.Join(m.dataDirectory, "*.json"))
-	return files
+// Recover finishes moving a completed timeslip left as pending by an
+// interrupted CompletePending or SwitchPending. As stashed timeslips are
+// always paused, one in progress at the top of the stash is the next timeslip
+// of a switch, and is restored as the pending timeslip.
+// It returns true if a timeslip was recovered.
+func (m Manager) Recover() (bool, error) {
+	data, err := m.store.PendingTimeSlip()
+	if err != nil {
+		return false, err
+	}
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 {
+		slip := &timeslip.Slip{}
+		if err := timeslip.Unmarshal(data, slip); err != nil || slip.Status != status.Completed {
+			return false, nil
+		}
+
+		saved, err := m.containsSlip(slip.Project, slip.UUID)
+		if err != nil {
+			return false, err
+		}
+
+		if !saved {
+			if err := m.SaveCompleted(slip.Project, data); err != nil {
+				return false, fmt.Errorf("unable to recover completed timeslip: %v", err)
+			}
+		}
+	}
+
+	stashed, err := m.StashedTimeSlips()
+	if err != nil {
+		return false, err
+	}
+
+	var next []byte
+	if len(stashed) > 0 && inProgress(stashed[0]) {
+		next, stashed = stashed[0], stashed[1:]
+	}
+
+	if len(data) == 0 && next == nil {
+		return false, nil
+	}
+
+	if err := m.store.SavePendingStack(next, stashed); err != nil {
+		return false, fmt.Errorf("pending timeslip may not have been deleted")
+	}
+
+	return true, nil
+}
+
+// FindCompleted returns the completed timeslip with a UUID starting with the
+// prefix, which must match only one timeslip.
+func (m Manager) FindCompleted(prefix string) ([]byte, error) {
+	if strings.TrimSpace(prefix) == "" {
+		return nil, fmt.Errorf("missing timeslip UUID")
+	}
+
+	projects, err := m.store.Projects()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []byte
+	matches := 0
+
+	for _, project := range projects {
+		err := m.store.Slips(project, time.Time{}, time.Time{}, func(data []byte) error {
+			if matchesUUID(slipUUID(data), prefix) {
+				found = append([]byte{}, data...)
+				matches++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch matches {
+	case 0:
+		return nil, errSlipNotFound(prefix)
+	case 1:
+		return found, nil
+	default:
+		return nil, fmt.Errorf("UUID '%s' matches %d timeslips, use a longer prefix", prefix, matches)
+	}
+}
+
+// ReplaceCompleted saves the completed timeslip in place of the one with the
+// same UUID in its project.
+func (m Manager) ReplaceCompleted(slip []byte) error {
+	ts := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(slip, ts); err != nil {
+		return fmt.Errorf("invalid timeslip data: %v", err)
+	}
+
+	if err := m.store.ReplaceCompleted(ts.Project, ts.UUID, slip); err != nil {
+		return fmt.Errorf("unable to save completed timeslip: %v", err)
+	}
+	return nil
 }
 
-// ProjectFilename returns the file name for the requested project.
-func (m Manager) ProjectFilename(projectName string) (string, bool) {
-	filename := filepath.Join(m.dataDirectory, toSnakeCase(projectName)+".json")
+// Projects returns the keys for all projects, as used by Slips.
+func (m Manager) Projects() ([]string, error) {
+	return m.store.Projects()
+}
 
-	_, err := os.Stat(filename)
+// ProjectExists returns true if the project has any completed timeslips.
+func (m Manager) ProjectExists(project string) bool {
+	projects, err := m.store.Projects()
 	if err != nil {
-		return "", false
+		return false
+	}
+
+	for _, p := range projects {
+		if SameProject(p, project) {
+			return true
+		}
 	}
+	return false
+}
+
+// SameProject returns true if the project names are for the same project,
+// which are saved in snake case, e.g. `MyProject` and `my_project`.
+func SameProject(a, b string) bool {
+	return toSnakeCase(a) == toSnakeCase(b)
+}
 
-	return filename, true
+// Slips calls fn for each completed timeslip of the project worked on between
+// the from/to times. A zero from or to time is unbounded.
+func (m Manager) Slips(project string, from, to time.Time, fn func(slip []byte) error) error {
+	return m.store.Slips(project, from, to, fn)
+}
+
+// Returns true if a timeslip with the UUID is saved to the project.
+func (m Manager) containsSlip(project, uuid string) (bool, error) {
+	found := false
+
+	err := m.store.Slips(project, time.Time{}, time.Time{}, func(data []byte) error {
+		slip := &timeslip.Slip{}
+		if err := timeslip.Unmarshal(data, slip); err == nil && slip.UUID == uuid {
+			found = true
+		}
+		return nil
+	})
+
+	return found, err
+}
+
+// Returns true if the UUID starts with the prefix, ignoring case.
+func matchesUUID(uuid, prefix string) bool {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	return prefix != "" && strings.HasPrefix(strings.ToLower(uuid), prefix)
+}
+
+// Returns true if the timeslip data is for a started or resumed timeslip.
+func inProgress(data []byte) bool {
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return false
+	}
+	return slip.Status == status.Started || slip.Status == status.Resumed
 }
 
 var exp = regexp.MustCompile("([a-z0-9]+)([A-Z])")
@@ -0,0 +1,940 @@
+package manager
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/mrcook/time_warrior/configuration"
+)
+
+// Runs the test against a new manager for each storage backend.
+func forEachStore(t *testing.T, test func(t *testing.T, m *Manager)) {
+	backends := map[string]func(dir string) (Store, error){
+		JSONLStorage: func(dir string) (Store, error) {
+			pending := filepath.Join(dir, ".pending")
+			if err := os.WriteFile(pending, []byte{}, 0644); err != nil {
+				return nil, err
+			}
+			return newJSONLStore(dir, pending), nil
+		},
+		BoltStorage: func(dir string) (Store, error) {
+			return newBoltStore(filepath.Join(dir, "time_warrior.db"))
+		},
+	}
+
+	for name, newStore := range backends {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			store, err := newStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := New(store, Files{
+				Lock:    filepath.Join(dir, ".lock"),
+				Trash:   filepath.Join(dir, ".trash"),
+				Journal: filepath.Join(dir, ".journal"),
+			})
+			defer m.Close()
+
+			test(t, m)
+		})
+	}
+}
+
+// Returns the JSON data for all timeslips in the project.
+func projectSlips(t *testing.T, m *Manager, project string) []string {
+	var slips []string
+
+	err := m.Slips(project, time.Time{}, time.Time{}, func(slip []byte) error {
+		slips = append(slips, string(slip))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return slips
+}
+
+// Returns true if there is a pending timeslip.
+func pendingExists(t *testing.T, m *Manager) bool {
+	exists, err := m.PendingTimeSlipExists()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
+
+const (
+	completedSlip = `{"project":"TimeWarrior","task":"Recover","started":100,"finished":200,"status":"completed","uuid":"0d8e895e-d3db-4887-86e3-8bb7f63ba101"}`
+	nextSlip      = `{"project":"TimeWarrior","task":"Next","started":200,"modified":200,"status":"started","uuid":"5b0b8e0a-3f4c-4d8e-9a51-1c2e7f6d9b02"}`
+)
+
+func TestManager_SavePending(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		if pendingExists(t, m) {
+			t.Fatal("expected no pending timeslip")
+		}
+
+		if err := m.SavePending([]byte(`{"project":"First"}`)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if err := m.SavePending([]byte(`{"project":"Second"}`)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		slip, err := m.PendingTimeSlip()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if string(slip) != `{"project":"Second"}` {
+			t.Errorf("expected pending timeslip to be replaced, got '%s'", slip)
+		}
+
+		if err := m.DeletePending(); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if pendingExists(t, m) {
+			t.Error("expected pending timeslip to have been deleted")
+		}
+	})
+}
+
+func TestManager_Stash(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		_ = m.SavePending([]byte(`{"project":"First"}`))
+
+		if err := m.StashPending([]byte(`{"project":"First","status":"paused"}`)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if pendingExists(t, m) {
+			t.Error("expected no pending timeslip after stashing")
+		}
+
+		_ = m.SavePending([]byte(`{"project":"Second"}`))
+		if err := m.StashPending([]byte(`{"project":"Second","status":"paused"}`)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		_ = m.SavePending([]byte(`{"project":"Third"}`))
+
+		stashed, err := m.StashedTimeSlips()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if len(stashed) != 2 || string(stashed[0]) != `{"project":"Second","status":"paused"}` {
+			t.Fatalf("expected the most recent timeslip first, got %q", stashed)
+		}
+
+		if err := m.PopStash([]byte(`{"project":"Second","status":"resumed"}`)); err == nil {
+			t.Error("expected an error when a pending timeslip exists")
+		}
+
+		_ = m.DeletePending()
+		if err := m.PopStash([]byte(`{"project":"Second","status":"resumed"}`)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		slip, _ := m.PendingTimeSlip()
+		if string(slip) != `{"project":"Second","status":"resumed"}` {
+			t.Errorf("expected the stashed timeslip to be pending, got '%s'", slip)
+		}
+		if stashed, _ := m.StashedTimeSlips(); len(stashed) != 1 || string(stashed[0]) != `{"project":"First","status":"paused"}` {
+			t.Errorf("expected 1 stashed timeslip to remain, got %q", stashed)
+		}
+	})
+}
+
+func TestManager_PopEmptyStash(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		if err := m.PopStash([]byte(`{"project":"First"}`)); err == nil {
+			t.Error("expected an error for an empty stash")
+		}
+	})
+}
+
+func TestManager_CompletePending(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		if err := m.CompletePending("TimeWarrior", []byte(completedSlip)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if pendingExists(t, m) {
+			t.Error("expected pending timeslip to have been deleted")
+		}
+
+		if !m.ProjectExists("TimeWarrior") {
+			t.Error("expected the project to exist")
+		}
+
+		if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 || slips[0] != completedSlip {
+			t.Errorf("expected the completed timeslip to be saved, got %v", slips)
+		}
+	})
+}
+
+func TestManager_SwitchPending(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		_ = m.StashPending([]byte(`{"project":"Stashed","status":"paused"}`))
+		_ = m.SavePending([]byte(`{"project":"TimeWarrior","status":"started"}`))
+
+		if err := m.SwitchPending("TimeWarrior", []byte(completedSlip), []byte(nextSlip)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if slip, _ := m.PendingTimeSlip(); string(slip) != nextSlip {
+			t.Errorf("expected the next timeslip to be pending, got '%s'", slip)
+		}
+		if stashed, _ := m.StashedTimeSlips(); len(stashed) != 1 {
+			t.Errorf("expected the stash to be unchanged, got %q", stashed)
+		}
+		if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 || slips[0] != completedSlip {
+			t.Errorf("expected the completed timeslip to be saved, got %v", slips)
+		}
+	})
+}
+
+func TestManager_Slips(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		_ = m.SaveCompleted("TimeWarrior", []byte(`{"project":"TimeWarrior","task":"First","started":100,"finished":200}`))
+		_ = m.SaveCompleted("TimeWarrior", []byte(`{"project":"TimeWarrior","task":"Second","started":300,"finished":400}`))
+		_ = m.SaveCompleted("OtherProject", []byte(`{"project":"OtherProject","started":300,"finished":400}`))
+
+		projects, err := m.Projects()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if len(projects) != 2 || projects[0] != "other_project" || projects[1] != "time_warrior" {
+			t.Errorf("expected project keys, got %v", projects)
+		}
+
+		var tasks []string
+		from, to := time.Unix(150, 0), time.Unix(250, 0)
+		err = m.Slips("TimeWarrior", from, to, func(slip []byte) error {
+			tasks = append(tasks, string(slip))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if len(tasks) != 1 {
+			t.Errorf("expected 1 timeslip within the time range, got %d", len(tasks))
+		}
+
+		if slips := projectSlips(t, m, "Missing"); len(slips) != 0 {
+			t.Errorf("expected no timeslips for a missing project, got %d", len(slips))
+		}
+	})
+}
+
+func TestSameProject(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"TimeWarrior", "TimeWarrior", true},
+		{"TimeWarrior", "time_warrior", true},
+		{"timeWarrior", "TimeWarrior", true},
+		{"TimeWarrior", "Other", false},
+	}
+
+	for _, tt := range tests {
+		if same := SameProject(tt.a, tt.b); same != tt.expected {
+			t.Errorf("expected %t for '%s' and '%s', got %t", tt.expected, tt.a, tt.b, same)
+		}
+	}
+}
+
+func TestManager_ReplaceCompleted(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		other := `{"project":"TimeWarrior","task":"Other","started":300,"finished":400,"status":"completed","uuid":"0d8e895e-aaaa-4887-86e3-8bb7f63ba101"}`
+		_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+		_ = m.SaveCompleted("TimeWarrior", []byte(other))
+
+		slip, err := m.FindCompleted("0D8E895E-D3")
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if string(slip) != completedSlip {
+			t.Errorf("expected the timeslip with the UUID prefix, got '%s'", slip)
+		}
+
+		if _, err := m.FindCompleted("0d8e895e"); err == nil {
+			t.Error("expected an error for an ambiguous UUID prefix")
+		}
+		if _, err := m.FindCompleted("ffff"); err == nil {
+			t.Error("expected an error for an unknown UUID")
+		}
+
+		edited := `{"project":"TimeWarrior","task":"Edited","started":100,"finished":900,"status":"completed","uuid":"0d8e895e-d3db-4887-86e3-8bb7f63ba101"}`
+		if err := m.ReplaceCompleted([]byte(edited)); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 2 || slips[0] != edited && slips[1] != edited {
+			t.Errorf("expected the timeslip to be replaced, got %v", slips)
+		}
+
+		var tasks []string
+		_ = m.Slips("TimeWarrior", time.Unix(800, 0), time.Time{}, func(slip []byte) error {
+			tasks = append(tasks, string(slip))
+			return nil
+		})
+		if len(tasks) != 1 || tasks[0] != edited {
+			t.Errorf("expected the new finished time to be used, got %v", tasks)
+		}
+	})
+}
+
+func TestManager_Recover(t *testing.T) {
+	t.Run("when the completed timeslip was not saved", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			_ = m.SavePending([]byte(completedSlip))
+
+			recovered, err := m.Recover()
+			if err != nil || !recovered {
+				t.Fatalf("expected timeslip to be recovered, got %t, '%v'", recovered, err)
+			}
+
+			if pendingExists(t, m) {
+				t.Error("expected pending timeslip to have been deleted")
+			}
+
+			if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 {
+				t.Errorf("expected 1 timeslip to be saved, got %d", len(slips))
+			}
+		})
+	})
+
+	t.Run("when the completed timeslip was already saved", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+			_ = m.SavePending([]byte(completedSlip))
+
+			recovered, err := m.Recover()
+			if err != nil || !recovered {
+				t.Fatalf("expected timeslip to be recovered, got %t, '%v'", recovered, err)
+			}
+
+			if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 {
+				t.Errorf("expected the timeslip not to be duplicated, got %d", len(slips))
+			}
+		})
+	})
+
+	t.Run("when a switch was interrupted", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			_ = m.StashPending([]byte(`{"project":"Stashed","status":"paused"}`))
+			stashed, _ := m.StashedTimeSlips()
+			_ = m.store.SavePendingStack([]byte(completedSlip), append([][]byte{[]byte(nextSlip)}, stashed...))
+
+			recovered, err := m.Recover()
+			if err != nil || !recovered {
+				t.Fatalf("expected timeslip to be recovered, got %t, '%v'", recovered, err)
+			}
+
+			if slip, _ := m.PendingTimeSlip(); string(slip) != nextSlip {
+				t.Errorf("expected the next timeslip to be pending, got '%s'", slip)
+			}
+			if stashed, _ := m.StashedTimeSlips(); len(stashed) != 1 {
+				t.Errorf("expected the paused timeslip to stay stashed, got %q", stashed)
+			}
+			if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 {
+				t.Errorf("expected 1 timeslip to be saved, got %d", len(slips))
+			}
+		})
+	})
+
+	t.Run("when the pending timeslip is in progress", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			_ = m.SavePending([]byte(`{"project":"TimeWarrior","status":"started"}`))
+
+			recovered, err := m.Recover()
+			if err != nil || recovered {
+				t.Fatalf("expected nothing to be recovered, got %t, '%v'", recovered, err)
+			}
+
+			if !pendingExists(t, m) {
+				t.Error("expected pending timeslip to be unchanged")
+			}
+		})
+	})
+
+	t.Run("when the pending timeslip can not be read", func(t *testing.T) {
+		dir := t.TempDir()
+		m := New(newJSONLStore(dir, filepath.Join(dir, ".pending")), Files{})
+
+		if recovered, err := m.Recover(); err == nil || recovered {
+			t.Errorf("expected a read error, got %t, '%v'", recovered, err)
+		}
+		if _, err := m.PendingTimeSlipExists(); err == nil {
+			t.Error("expected a read error")
+		}
+	})
+}
+
+func TestManager_Lock(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		unlock, err := m.Lock()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		unlock()
+
+		unlock, err = m.Lock()
+		if err != nil {
+			t.Fatalf("expected lock to be released, got '%s'", err)
+		}
+		unlock()
+	})
+}
+
+func TestJSONLStore_PendingFileFormat(t *testing.T) {
+	dir := t.TempDir()
+	pending := filepath.Join(dir, ".pending")
+	store := newJSONLStore(dir, pending)
+
+	_ = os.WriteFile(pending, []byte(`{"project":"Legacy"}`), 0644)
+	if slip, err := store.PendingTimeSlip(); err != nil || string(slip) != `{"project":"Legacy"}` {
+		t.Errorf("expected a single timeslip file to be read, got '%s', '%v'", slip, err)
+	}
+
+	_ = store.SavePendingStack(nil, [][]byte{[]byte(`{"project":"Legacy"}`)})
+	if data, _ := os.ReadFile(pending); string(data) != "\n{\"project\":\"Legacy\"}\n" {
+		t.Errorf("expected a blank pending line followed by the stash, got %q", data)
+	}
+
+	_ = store.SavePendingStack([]byte(`{"project":"Legacy"}`), nil)
+	if data, _ := os.ReadFile(pending); string(data) != `{"project":"Legacy"}` {
+		t.Errorf("expected only the pending timeslip without a stash, got %q", data)
+	}
+}
+
+func TestJSONLStore_SavePendingIsAtomic(t *testing.T) {
+	dir := t.TempDir()
+	store := newJSONLStore(dir, filepath.Join(dir, ".pending"))
+
+	if err := store.SavePending([]byte(`{"project":"Atomic"}`)); err != nil {
+		t.Fatalf("unexpected error, got '%s'", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*tmp*"))
+	if len(files) != 0 {
+		t.Errorf("expected temporary files to be removed, got %v", files)
+	}
+}
+
+func TestJSONLStore_SavePendingKeepsPermissions(t *testing.T) {
+	dir := t.TempDir()
+	pending := filepath.Join(dir, ".pending")
+	if err := os.WriteFile(pending, []byte{}, 0640); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chmod(pending, 0640)
+
+	store := newJSONLStore(dir, pending)
+	if err := store.SavePending([]byte(`{"project":"Atomic"}`)); err != nil {
+		t.Fatalf("unexpected error, got '%s'", err)
+	}
+	if info, _ := os.Stat(pending); info.Mode().Perm() != 0640 {
+		t.Errorf("expected the file permissions to be kept, got %v", info.Mode().Perm())
+	}
+
+	trash := filepath.Join(dir, ".trash")
+	if err := writeFileAtomic(trash, []byte("\n")); err != nil {
+		t.Fatalf("unexpected error, got '%s'", err)
+	}
+	if info, _ := os.Stat(trash); info.Mode().Perm() != 0644 {
+		t.Errorf("expected a new file to be 0644, got %v", info.Mode().Perm())
+	}
+}
+
+func TestBoltStore_SlipsSpanningPeriod(t *testing.T) {
+	store, err := newBoltStore(filepath.Join(t.TempDir(), "time_warrior.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	_ = store.SaveCompleted("TimeWarrior", []byte(`{"project":"TimeWarrior","task":"Long","started":100,"finished":1000}`))
+	_ = store.SaveCompleted("TimeWarrior", []byte(`{"project":"TimeWarrior","task":"Later","started":1100,"finished":1200}`))
+
+	_ = store.db.View(func(tx *bolt.Tx) error {
+		span := tx.Bucket(projectsBucket).Bucket([]byte("time_warrior")).Get(spanKey)
+		if span == nil || binary.BigEndian.Uint64(span) != 900 {
+			t.Errorf("expected the longest span to be saved, got %v", span)
+		}
+		return nil
+	})
+
+	var tasks []string
+	err = store.Slips("TimeWarrior", time.Unix(150, 0), time.Unix(250, 0), func(slip []byte) error {
+		tasks = append(tasks, string(slip))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error, got '%s'", err)
+	}
+	if len(tasks) != 1 || !strings.Contains(tasks[0], `"Long"`) {
+		t.Errorf("expected the timeslip spanning the time range, got %q", tasks)
+	}
+}
+
+func TestManager_Trash(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+		_ = m.SavePending([]byte(nextSlip))
+
+		if _, err := m.TrashCompleted("0d8e895e"); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 0 {
+			t.Errorf("expected the completed timeslip to be removed, got %v", slips)
+		}
+
+		if err := m.TrashPending(); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if pendingExists(t, m) {
+			t.Error("expected the pending timeslip to be removed")
+		}
+
+		trashed, err := m.TrashedTimeSlips()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if len(trashed) != 2 || trashed[0].Pending || !trashed[1].Pending || string(trashed[1].Slip) != nextSlip {
+			t.Fatalf("expected both timeslips in the trash, got %+v", trashed)
+		}
+
+		if _, err := m.RestoreTrashed("0d8e895e"); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 || slips[0] != completedSlip {
+			t.Errorf("expected the completed timeslip to be restored, got %v", slips)
+		}
+
+		if _, err := m.RestoreTrashed("5b0b"); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if slip, _ := m.PendingTimeSlip(); string(slip) != nextSlip {
+			t.Errorf("expected the pending timeslip to be restored, got '%s'", slip)
+		}
+
+		if trashed, _ := m.TrashedTimeSlips(); len(trashed) != 0 {
+			t.Errorf("expected the trash to be empty, got %+v", trashed)
+		}
+	})
+}
+
+func TestManager_RestoreExistingTimeSlip(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		_ = m.SavePending([]byte(nextSlip))
+		_ = m.TrashPending()
+		_ = m.CompletePending("TimeWarrior", []byte(strings.Replace(nextSlip, `"status":"started"`, `"status":"completed"`, 1)))
+
+		if _, err := m.RestoreTrashed("5b0b"); err == nil {
+			t.Error("expected an error restoring a timeslip which already exists")
+		}
+		if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 {
+			t.Errorf("expected the timeslip not to be duplicated, got %v", slips)
+		}
+	})
+}
+
+func TestManager_EmptyTrash(t *testing.T) {
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+		_, _ = m.TrashCompleted("0d8e")
+
+		if err := m.EmptyTrash(); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if _, err := m.RestoreTrashed("0d8e"); err == nil {
+			t.Error("expected an error restoring from an empty trash")
+		}
+	})
+}
+
+func TestManager_Undo(t *testing.T) {
+	t.Run("undo in order", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			op, _ := m.BeginOperation("start")
+			_ = m.SavePending([]byte(nextSlip))
+			if err := m.Record(op, nil, nil); err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+
+			op, _ = m.BeginOperation("done")
+			_ = m.CompletePending("TimeWarrior", []byte(completedSlip))
+			if err := m.Record(op, nil, []byte(completedSlip)); err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+
+			undoable, err := m.UndoableOperations()
+			if err != nil || len(undoable) != 2 || undoable[0].Name != "done" {
+				t.Fatalf("expected 2 operations, most recent first, got %+v, '%v'", undoable, err)
+			}
+
+			if op, err := m.Undo(); err != nil || op.Name != "done" {
+				t.Fatalf("expected done to be undone, got %+v, '%v'", op, err)
+			}
+			if slip, _ := m.PendingTimeSlip(); string(slip) != nextSlip {
+				t.Errorf("expected the pending timeslip to be restored, got '%s'", slip)
+			}
+			if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 0 {
+				t.Errorf("expected the completed timeslip to be removed, got %v", slips)
+			}
+
+			if op, err := m.Undo(); err != nil || op.Name != "start" {
+				t.Fatalf("expected start to be undone, got %+v, '%v'", op, err)
+			}
+			if pendingExists(t, m) {
+				t.Error("expected no pending timeslip")
+			}
+
+			if _, err := m.Undo(); err == nil || err.Error() != "nothing to undo" {
+				t.Errorf("expected nothing to undo, got '%v'", err)
+			}
+		})
+	})
+
+	t.Run("undo an edit", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			edited := `{"project":"TimeWarrior","task":"Edited","started":100,"finished":200,"status":"completed","uuid":"0d8e895e-d3db-4887-86e3-8bb7f63ba101"}`
+			_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+
+			op, _ := m.BeginOperation("edit")
+			_ = m.ReplaceCompleted([]byte(edited))
+			_ = m.Record(op, []byte(completedSlip), []byte(edited))
+
+			if _, err := m.Undo(); err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+			if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 1 || slips[0] != completedSlip {
+				t.Errorf("expected the original timeslip, got %v", slips)
+			}
+		})
+	})
+
+	t.Run("undo a trash restore", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+			_, _ = m.TrashCompleted("0d8e")
+
+			op, _ := m.BeginOperation("trash restore")
+			data, err := m.RestoreTrashed("0d8e")
+			if err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+			_ = m.Record(op, nil, data)
+
+			if _, err := m.Undo(); err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+			if slips := projectSlips(t, m, "TimeWarrior"); len(slips) != 0 {
+				t.Errorf("expected the timeslip to be removed from the project, got %v", slips)
+			}
+			if trashed, _ := m.TrashedTimeSlips(); len(trashed) != 1 || string(trashed[0].Slip) != completedSlip {
+				t.Errorf("expected the timeslip to be back in the trash, got %+v", trashed)
+			}
+		})
+	})
+
+	t.Run("undo a pending delete", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			_ = m.SavePending([]byte(nextSlip))
+
+			op, _ := m.BeginOperation("delete")
+			_ = m.TrashPending()
+			_ = m.Record(op, nil, nil)
+
+			if _, err := m.Undo(); err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+			if slip, _ := m.PendingTimeSlip(); string(slip) != nextSlip {
+				t.Errorf("expected the pending timeslip to be restored, got '%s'", slip)
+			}
+			if trashed, _ := m.TrashedTimeSlips(); len(trashed) != 0 {
+				t.Errorf("expected the timeslip to be removed from the trash, got %+v", trashed)
+			}
+		})
+	})
+
+	t.Run("when the timeslip has changed since", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			op, _ := m.BeginOperation("start")
+			_ = m.SavePending([]byte(nextSlip))
+			_ = m.Record(op, nil, nil)
+
+			_ = m.SavePending([]byte(`{"project":"Changed","status":"paused"}`))
+
+			if _, err := m.Undo(); err == nil {
+				t.Error("expected an error undoing a changed timeslip")
+			}
+			if slip, _ := m.PendingTimeSlip(); string(slip) != `{"project":"Changed","status":"paused"}` {
+				t.Errorf("expected the pending timeslip to be unchanged, got '%s'", slip)
+			}
+		})
+	})
+
+	t.Run("operations without changes are not recorded", func(t *testing.T) {
+		forEachStore(t, func(t *testing.T, m *Manager) {
+			op, _ := m.BeginOperation("pause")
+			_ = m.Record(op, nil, nil)
+
+			if ops, _ := m.Journal(); len(ops) != 0 {
+				t.Errorf("expected an empty journal, got %+v", ops)
+			}
+		})
+	})
+}
+
+// Returns the pending, stashed, completed, and trashed timeslips, for
+// comparing the state before and after an undo.
+func timeslipState(t *testing.T, m *Manager) string {
+	pending, stashed, err := m.pendingState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashed, err := m.trashState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fmt.Sprintf("pending: %s\nstashed: %s\ncompleted: %v\ntrash: %q",
+		pending, stashed, projectSlips(t, m, "TimeWarrior"), trashed)
+}
+
+func TestManager_UndoEachOperation(t *testing.T) {
+	paused := `{"project":"TimeWarrior","task":"Next","started":200,"worked":50,"modified":250,"status":"paused","uuid":"5b0b8e0a-3f4c-4d8e-9a51-1c2e7f6d9b02"}`
+	edited := strings.Replace(completedSlip, `"task":"Recover"`, `"task":"Edited"`, 1)
+
+	tests := []struct {
+		name   string
+		setup  func(m *Manager)
+		action func(m *Manager) (before, after []byte)
+	}{
+		{
+			name:   "start",
+			setup:  func(m *Manager) {},
+			action: func(m *Manager) ([]byte, []byte) { _ = m.SavePending([]byte(nextSlip)); return nil, nil },
+		},
+		{
+			name:   "pause",
+			setup:  func(m *Manager) { _ = m.SavePending([]byte(nextSlip)) },
+			action: func(m *Manager) ([]byte, []byte) { _ = m.SavePending([]byte(paused)); return nil, nil },
+		},
+		{
+			name:  "done",
+			setup: func(m *Manager) { _ = m.SavePending([]byte(nextSlip)) },
+			action: func(m *Manager) ([]byte, []byte) {
+				_ = m.CompletePending("TimeWarrior", []byte(completedSlip))
+				return nil, []byte(completedSlip)
+			},
+		},
+		{
+			name:  "add",
+			setup: func(m *Manager) {},
+			action: func(m *Manager) ([]byte, []byte) {
+				_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+				return nil, []byte(completedSlip)
+			},
+		},
+		{
+			name:  "edit",
+			setup: func(m *Manager) { _ = m.SaveCompleted("TimeWarrior", []byte(completedSlip)) },
+			action: func(m *Manager) ([]byte, []byte) {
+				_ = m.ReplaceCompleted([]byte(edited))
+				return []byte(completedSlip), []byte(edited)
+			},
+		},
+		{
+			name:   "delete pending",
+			setup:  func(m *Manager) { _ = m.SavePending([]byte(nextSlip)) },
+			action: func(m *Manager) ([]byte, []byte) { _ = m.TrashPending(); return nil, nil },
+		},
+		{
+			name:  "delete completed",
+			setup: func(m *Manager) { _ = m.SaveCompleted("TimeWarrior", []byte(completedSlip)) },
+			action: func(m *Manager) ([]byte, []byte) {
+				data, _ := m.TrashCompleted("0d8e")
+				return data, nil
+			},
+		},
+		{
+			name: "trash restore",
+			setup: func(m *Manager) {
+				_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+				_, _ = m.TrashCompleted("0d8e")
+			},
+			action: func(m *Manager) ([]byte, []byte) {
+				data, _ := m.RestoreTrashed("0d8e")
+				return nil, data
+			},
+		},
+		{
+			name:  "switch",
+			setup: func(m *Manager) { _ = m.SavePending([]byte(`{"project":"TimeWarrior","status":"started"}`)) },
+			action: func(m *Manager) ([]byte, []byte) {
+				_ = m.SwitchPending("TimeWarrior", []byte(completedSlip), []byte(nextSlip))
+				return nil, []byte(completedSlip)
+			},
+		},
+		{
+			name:   "stash",
+			setup:  func(m *Manager) { _ = m.SavePending([]byte(nextSlip)) },
+			action: func(m *Manager) ([]byte, []byte) { _ = m.StashPending([]byte(paused)); return nil, nil },
+		},
+		{
+			name:   "stash pop",
+			setup:  func(m *Manager) { _ = m.StashPending([]byte(paused)) },
+			action: func(m *Manager) ([]byte, []byte) { _ = m.PopStash([]byte(nextSlip)); return nil, nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachStore(t, func(t *testing.T, m *Manager) {
+				tt.setup(m)
+				before := timeslipState(t, m)
+
+				op, err := m.BeginOperation(tt.name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				completedBefore, completedAfter := tt.action(m)
+				if err := m.Record(op, completedBefore, completedAfter); err != nil {
+					t.Fatalf("unexpected error, got '%s'", err)
+				}
+				if timeslipState(t, m) == before {
+					t.Fatal("expected the operation to change the timeslips")
+				}
+
+				if _, err := m.Undo(); err != nil {
+					t.Fatalf("unexpected error, got '%s'", err)
+				}
+				if after := timeslipState(t, m); after != before {
+					t.Errorf("expected the timeslips to be as before the operation\nbefore:\n%s\nafter undo:\n%s", before, after)
+				}
+			})
+		})
+	}
+}
+
+func TestManager_JournalPruning(t *testing.T) {
+	defer func(size int64) { maxJournalSize = size }(maxJournalSize)
+	maxJournalSize = 2048
+
+	forEachStore(t, func(t *testing.T, m *Manager) {
+		for i := 0; i < 20; i++ {
+			op, _ := m.BeginOperation("start")
+			_ = m.SavePending([]byte(fmt.Sprintf(`{"project":"TimeWarrior","started":%d,"status":"started"}`, i)))
+			if err := m.Record(op, nil, nil); err != nil {
+				t.Fatalf("unexpected error, got '%s'", err)
+			}
+		}
+
+		info, err := os.Stat(m.files.Journal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > maxJournalSize {
+			t.Errorf("expected the journal to be pruned, got %d bytes", info.Size())
+		}
+
+		ops, err := m.Journal()
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if len(ops) == 0 || len(ops) == 20 || ops[len(ops)-1].ID != 20 {
+			t.Errorf("expected only the most recent operations, numbered to 20, got %+v", ops)
+		}
+
+		if _, err := m.Undo(); err != nil {
+			t.Errorf("expected the most recent operation to be undone, got '%s'", err)
+		}
+	})
+}
+
+func TestManager_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	cfg := configuration.New()
+	if err := cfg.SetDataDirectory(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfg.PendingFilePath(), []byte{}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New(newJSONLStore(dir, cfg.PendingFilePath()), Files{})
+	_ = m.SaveCompleted("TimeWarrior", []byte(completedSlip))
+	_ = m.SaveCompleted("OtherProject", []byte(`{"project":"OtherProject","started":300,"finished":400,"uuid":"other"}`))
+	_ = m.StashPending([]byte(`{"project":"Stashed","status":"paused"}`))
+	_ = m.SavePending([]byte(nextSlip))
+
+	if _, err := m.Migrate(cfg, JSONLStorage); err == nil {
+		t.Error("expected an error migrating to the same backend")
+	}
+
+	copied, err := m.Migrate(cfg, BoltStorage)
+	if err != nil {
+		t.Fatalf("unexpected error, got '%s'", err)
+	}
+	if copied != 2 {
+		t.Errorf("expected 2 completed timeslips to be copied, got %d", copied)
+	}
+
+	store, err := newBoltStore(cfg.DatabaseFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := New(store, Files{})
+
+	if slip, _ := db.PendingTimeSlip(); string(slip) != nextSlip {
+		t.Errorf("expected the pending timeslip to be copied, got '%s'", slip)
+	}
+	if stashed, _ := db.StashedTimeSlips(); len(stashed) != 1 {
+		t.Errorf("expected the stashed timeslip to be copied, got %q", stashed)
+	}
+	if projects, _ := db.Projects(); fmt.Sprint(projects) != "[other_project time_warrior]" {
+		t.Errorf("expected the projects to be copied, got %v", projects)
+	}
+	if slips := projectSlips(t, db, "TimeWarrior"); len(slips) != 1 || slips[0] != completedSlip {
+		t.Errorf("expected the completed timeslip to be copied, got %q", slips)
+	}
+	db.Close()
+
+	if _, err := m.Migrate(cfg, BoltStorage); err == nil {
+		t.Error("expected an error migrating to a backend with timeslips")
+	}
+}
+
+func TestManager_MigrateInvalidTimeSlip(t *testing.T) {
+	dir := t.TempDir()
+	from := newJSONLStore(dir, filepath.Join(dir, ".pending"))
+	_ = os.WriteFile(filepath.Join(dir, ".pending"), []byte{}, 0644)
+	_ = from.SaveCompleted("TimeWarrior", []byte(completedSlip))
+	_ = from.SaveCompleted("TimeWarrior", []byte(`{"project":`))
+
+	to, err := newBoltStore(filepath.Join(dir, "time_warrior.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer to.Close()
+
+	if _, err := copyTimeSlips(from, to); err == nil {
+		t.Fatal("expected an error for the invalid timeslip")
+	}
+	if projects, _ := to.Projects(); len(projects) != 0 {
+		t.Errorf("expected nothing to be copied, got %v", projects)
+	}
+}
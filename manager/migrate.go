@@ -0,0 +1,101 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mrcook/time_warrior/configuration"
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+// Migrate copies the pending, stashed, and completed timeslips to another
+// storage backend in the same data directory, returning the number of
+// completed timeslips copied. The other backend must not have any timeslips,
+// and the timeslips are left in the current backend.
+func (m Manager) Migrate(cfg *configuration.Config, backend string) (int, error) {
+	if backend == cfg.StorageBackend() {
+		return 0, fmt.Errorf("timeslips are already saved to the %s storage backend", backend)
+	}
+
+	target, err := newStore(cfg, backend)
+	if err != nil {
+		return 0, err
+	}
+	defer target.Close()
+
+	return copyTimeSlips(m.store, target)
+}
+
+// Copies all timeslips from one store to another, empty, store. Every
+// timeslip is checked before any are copied, so a bad timeslip does not leave
+// the timeslips half copied.
+func copyTimeSlips(from, to Store) (int, error) {
+	if err := checkEmpty(to); err != nil {
+		return 0, err
+	}
+
+	pending, err := from.PendingTimeSlip()
+	if err != nil {
+		return 0, err
+	}
+	stashed, err := from.StashedTimeSlips()
+	if err != nil {
+		return 0, err
+	}
+	projects, err := from.Projects()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, project := range projects {
+		err := from.Slips(project, time.Time{}, time.Time{}, func(data []byte) error {
+			if err := timeslip.Unmarshal(data, &timeslip.Slip{}); err != nil {
+				return fmt.Errorf("invalid timeslip in project '%s', nothing was copied: %s", project, data)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if len(pending) > 0 || len(stashed) > 0 {
+		if err := to.SavePendingStack(pending, stashed); err != nil {
+			return 0, err
+		}
+	}
+
+	copied := 0
+	for _, project := range projects {
+		err := from.Slips(project, time.Time{}, time.Time{}, func(data []byte) error {
+			copied++
+			return to.SaveCompleted(project, data)
+		})
+		if err != nil {
+			return copied, fmt.Errorf("unable to copy project '%s': %v", project, err)
+		}
+	}
+
+	return copied, nil
+}
+
+// Returns an error if the store has any timeslips.
+func checkEmpty(s Store) error {
+	pending, err := s.PendingTimeSlip()
+	if err != nil {
+		return err
+	}
+	stashed, err := s.StashedTimeSlips()
+	if err != nil {
+		return err
+	}
+	projects, err := s.Projects()
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 || len(stashed) > 0 || len(projects) > 0 {
+		return fmt.Errorf("the storage backend already has timeslips, they can only be copied to an empty one")
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+// Store is a storage backend for the pending and completed timeslips.
+// Timeslips are passed to/from a store as their JSON data.
+type Store interface {
+	// PendingTimeSlip returns the pending timeslip, which is empty when there is none.
+	PendingTimeSlip() ([]byte, error)
+
+	// SavePending replaces the pending timeslip.
+	SavePending(slip []byte) error
+
+	// DeletePending removes the pending timeslip.
+	DeletePending() error
+
+	// StashedTimeSlips returns the stashed timeslips, most recent first.
+	StashedTimeSlips() ([][]byte, error)
+
+	// SavePendingStack replaces both the pending and the stashed timeslips in
+	// a single write. An empty pending timeslip clears it.
+	SavePendingStack(pending []byte, stashed [][]byte) error
+
+	// SaveCompleted appends a completed timeslip to the project.
+	SaveCompleted(project string, slip []byte) error
+
+	// ReplaceCompleted replaces the completed timeslip of the project having
+	// the UUID, or removes it when the given timeslip is empty.
+	ReplaceCompleted(project, uuid string, slip []byte) error
+
+	// Projects returns the keys of all projects with completed timeslips.
+	Projects() ([]string, error)
+
+	// Slips calls fn for each completed timeslip of the project worked on
+	// between the from/to times. A zero from or to time is unbounded.
+	// Timeslips that can not be parsed are always included, so that callers
+	// are able to report them. The data is only valid during the call to fn.
+	Slips(project string, from, to time.Time, fn func(slip []byte) error) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+func errSlipNotFound(uuid string) error {
+	return fmt.Errorf("no completed timeslip found with UUID '%s'", uuid)
+}
+
+// Store backend names, as used in the configuration.
+const (
+	JSONLStorage = "jsonl"
+	BoltStorage  = "bolt"
+)
+
+// Returns the UUID of the timeslip data, which is empty when it can not be parsed.
+func slipUUID(data []byte) string {
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return ""
+	}
+	return slip.UUID
+}
+
+// Returns true if the timeslip data should be included for the from/to times.
+func withinTimeRange(data []byte, from, to time.Time) bool {
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return true
+	}
+
+	if !from.IsZero() && slip.Finished < int(from.Unix()) {
+		return false
+	}
+	if !to.IsZero() && slip.Started > int(to.Unix()) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,225 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+// TrashedSlip is a deleted timeslip, kept in the trash file until it is
+// restored or the trash is emptied.
+type TrashedSlip struct {
+	Deleted int             `json:"deleted"`
+	Pending bool            `json:"pending,omitempty"`
+	Slip    json.RawMessage `json:"slip"`
+}
+
+// TrashedTimeSlips returns the deleted timeslips, in the order deleted.
+func (m Manager) TrashedTimeSlips() ([]TrashedSlip, error) {
+	file, err := os.Open(m.files.Trash)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var trashed []TrashedSlip
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		t := TrashedSlip{}
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("invalid trash data: %v", err)
+		}
+		trashed = append(trashed, t)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read trash file: %v", err)
+	}
+
+	return trashed, nil
+}
+
+// TrashPending moves the pending timeslip to the trash.
+func (m Manager) TrashPending() error {
+	slip, err := m.store.PendingTimeSlip()
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(slip)) == 0 {
+		return fmt.Errorf("no pending timeslip found")
+	}
+
+	if err := m.addToTrash(slip, true); err != nil {
+		return err
+	}
+
+	return m.DeletePending()
+}
+
+// TrashCompleted moves the completed timeslip with a UUID starting with the
+// prefix to the trash, returning its data. The timeslip is added to the trash
+// before it is removed from the project, so it is never lost.
+func (m Manager) TrashCompleted(prefix string) ([]byte, error) {
+	data, err := m.FindCompleted(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(data, slip); err != nil {
+		return nil, fmt.Errorf("invalid timeslip data: %v", err)
+	}
+
+	if err := m.addToTrash(data, false); err != nil {
+		return nil, err
+	}
+
+	if err := m.store.ReplaceCompleted(slip.Project, slip.UUID, nil); err != nil {
+		return nil, fmt.Errorf("unable to delete completed timeslip: %v", err)
+	}
+
+	return data, nil
+}
+
+// RestoreTrashed moves the trashed timeslip with a UUID starting with the
+// prefix back to its project, or back to pending when it was the pending
+// timeslip, returning its data. A pending timeslip can only be restored
+// when there is no other pending timeslip, and a timeslip is never restored
+// when one with the same UUID already exists.
+func (m Manager) RestoreTrashed(prefix string) ([]byte, error) {
+	trashed, err := m.TrashedTimeSlips()
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, t := range trashed {
+		if matchesUUID(slipUUID(t.Slip), prefix) {
+			if index >= 0 {
+				return nil, fmt.Errorf("UUID '%s' matches more than one trashed timeslip, use a longer prefix", prefix)
+			}
+			index = i
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("no trashed timeslip found with UUID '%s'", prefix)
+	}
+
+	entry := trashed[index]
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(entry.Slip, slip); err != nil {
+		return nil, fmt.Errorf("invalid timeslip data: %v", err)
+	}
+
+	exists, err := m.slipExists(slip.Project, slip.UUID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("timeslip '%s' already exists, it can not be restored", slip.UUID)
+	}
+
+	if entry.Pending {
+		pending, err := m.PendingTimeSlipExists()
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return nil, fmt.Errorf("pending timeslip already exists, complete or stash it first")
+		}
+		if err := m.SavePending(entry.Slip); err != nil {
+			return nil, err
+		}
+	} else if err := m.SaveCompleted(slip.Project, entry.Slip); err != nil {
+		return nil, err
+	}
+
+	if err := m.saveTrash(append(trashed[:index:index], trashed[index+1:]...)); err != nil {
+		return nil, err
+	}
+
+	return entry.Slip, nil
+}
+
+// Returns true if the timeslip with the UUID is pending, stashed, or saved to
+// the project.
+func (m Manager) slipExists(project, uuid string) (bool, error) {
+	pending, err := m.store.PendingTimeSlip()
+	if err != nil {
+		return false, err
+	}
+	if slipUUID(pending) == uuid {
+		return true, nil
+	}
+
+	stashed, err := m.StashedTimeSlips()
+	if err != nil {
+		return false, err
+	}
+	for _, slip := range stashed {
+		if slipUUID(slip) == uuid {
+			return true, nil
+		}
+	}
+
+	return m.containsSlip(project, uuid)
+}
+
+// EmptyTrash permanently deletes all trashed timeslips.
+func (m Manager) EmptyTrash() error {
+	return m.saveTrash(nil)
+}
+
+// Appends the timeslip to the trash file.
+func (m Manager) addToTrash(slip []byte, pending bool) error {
+	data, err := json.Marshal(TrashedSlip{
+		Deleted: int(time.Now().Unix()),
+		Pending: pending,
+		Slip:    json.RawMessage(bytes.TrimSpace(slip)),
+	})
+	if err != nil {
+		return fmt.Errorf("invalid timeslip data: %v", err)
+	}
+
+	file, err := os.OpenFile(m.files.Trash, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return fmt.Errorf("unable to open trash file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("unable to save to trash file: %v", err)
+	}
+
+	return file.Sync()
+}
+
+// Replaces the trash file with the trashed timeslips.
+func (m Manager) saveTrash(trashed []TrashedSlip) error {
+	var data []byte
+
+	for _, t := range trashed {
+		line, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Errorf("invalid trash data: %v", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	if err := writeFileAtomic(m.files.Trash, data); err != nil {
+		return fmt.Errorf("unable to save trash file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package reports
+
+import "github.com/mrcook/time_warrior/timeslip"
+
+// Filter selects which timeslips are included in a report by their tags.
+type Filter struct {
+	Tags        []string // timeslips must have all of these tags
+	ExcludeTags []string // timeslips must have none of these tags
+}
+
+// Matches returns true if a timeslip with the given tags passes the filter.
+func (f Filter) Matches(tags []string) bool {
+	for _, tag := range f.Tags {
+		if !timeslip.ContainsTag(tags, tag) {
+			return false
+		}
+	}
+
+	for _, tag := range f.ExcludeTags {
+		if timeslip.ContainsTag(tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,101 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	tests := map[string]struct {
+		filter   Filter
+		tags     []string
+		expected bool
+	}{
+		"no filter":                {Filter{}, []string{"meeting"}, true},
+		"no filter or tags":        {Filter{}, nil, true},
+		"has the tag":              {Filter{Tags: []string{"meeting"}}, []string{"billable", "meeting"}, true},
+		"missing the tag":          {Filter{Tags: []string{"meeting"}}, []string{"billable"}, false},
+		"has all of the tags":      {Filter{Tags: []string{"meeting", "billable"}}, []string{"billable", "meeting"}, true},
+		"has only one tag":         {Filter{Tags: []string{"meeting", "billable"}}, []string{"meeting"}, false},
+		"has an excluded tag":      {Filter{ExcludeTags: []string{"meeting"}}, []string{"billable", "meeting"}, false},
+		"without excluded tags":    {Filter{ExcludeTags: []string{"meeting"}}, []string{"billable"}, true},
+		"with no tags":             {Filter{ExcludeTags: []string{"meeting"}}, nil, true},
+		"has both tag and exclude": {Filter{Tags: []string{"billable"}, ExcludeTags: []string{"meeting"}}, []string{"billable", "meeting"}, false},
+		"ignores the tag case":     {Filter{Tags: []string{"Meeting"}}, []string{"meeting"}, true},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			if matches := test.filter.Matches(test.tags); matches != test.expected {
+				t.Errorf("expected %t, got %t", test.expected, matches)
+			}
+		})
+	}
+}
+
+// Returns a report for a week with a project of tagged timeslips, one of
+// which was saved with a capitalised tag.
+func newTaggedReport(t *testing.T, f Filter) *Report {
+	p, err := period.Parse("2026-W42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New(p)
+	r.Filter = f
+
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.Local)
+	standup := mustCompleted(t, "Alpha.standup", monday, monday.Add(15*time.Minute))
+	standup.Tags = []string{"Meeting"}
+	review := mustCompleted(t, "Alpha.review", monday.Add(time.Hour), monday.Add(2*time.Hour))
+	review.Tags = []string{"meeting", "billable"}
+	api := mustCompleted(t, "Alpha.api", monday.Add(3*time.Hour), monday.Add(5*time.Hour))
+
+	r.ProcessProject(func(fn func(slip []byte) error) error {
+		for _, s := range []*timeslip.Slip{standup, review, api} {
+			if err := fn(s.ToJson()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return r
+}
+
+func TestReport_ExcludeTag(t *testing.T) {
+	s := newTaggedReport(t, Filter{ExcludeTags: []string{"meeting"}}).Summary()
+
+	if len(s.Projects) != 1 || len(s.Projects[0].Tasks) != 1 {
+		t.Fatalf("expected only the untagged task, got %+v", s.Projects)
+	}
+	if s.Projects[0].Tasks[0].Name != "api" {
+		t.Errorf("expected the api task, got '%s'", s.Projects[0].Tasks[0].Name)
+	}
+	if s.Total.Seconds != 2*60*60 {
+		t.Errorf("expected 2 hours worked, got %d seconds", s.Total.Seconds)
+	}
+}
+
+func TestReport_GroupByTag(t *testing.T) {
+	r := newTaggedReport(t, Filter{})
+	r.GroupByTag = true
+	s := r.Summary()
+
+	expected := map[string]int{
+		"billable": 60 * 60,
+		"meeting":  75 * 60,
+		untagged:   2 * 60 * 60,
+	}
+
+	if len(s.Tags) != len(expected) {
+		t.Fatalf("expected %d tags, got %+v", len(expected), s.Tags)
+	}
+	for _, tag := range s.Tags {
+		if seconds, ok := expected[tag.Name]; !ok || tag.Seconds != seconds {
+			t.Errorf("unexpected time worked for tag '%s', got %d seconds", tag.Name, tag.Seconds)
+		}
+	}
+}
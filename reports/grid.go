@@ -0,0 +1,104 @@
+package reports
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+)
+
+// WeekSummary is a timesheet grid for a week, with a row for each project, or
+// each task for a single project report, and a column for each day.
+type WeekSummary struct {
+	Start string    `json:"start"`
+	Days  []string  `json:"days"`
+	Rows  []GridRow `json:"rows"`
+	Duration
+	DayTotals []Duration `json:"day_totals"`
+}
+
+// GridRow is the time worked on a project or task for each day of a week.
+type GridRow struct {
+	Name string `json:"name"`
+	Duration
+	Days []Duration `json:"days"`
+}
+
+// Returns a grid for each week of the days, starting on the configured first
+// day of the week.
+func weekSummaries(days []DaySummary, byTask bool) []WeekSummary {
+	weeks := make(map[string]map[string][]int)
+
+	for _, d := range days {
+		date, err := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		start := period.Period{}.BeginningOfWeek(date)
+		column := int(date.Sub(start).Hours()+12) / 24
+
+		week := start.Format("2006-01-02")
+		if weeks[week] == nil {
+			weeks[week] = make(map[string][]int)
+		}
+
+		for _, t := range d.Tasks {
+			name := t.Project
+			if byTask {
+				name = t.Task
+				if name == "" {
+					name = "."
+				}
+			}
+			if weeks[week][name] == nil {
+				weeks[week][name] = make([]int, 7)
+			}
+			weeks[week][name][column] += t.Seconds
+		}
+	}
+
+	var summaries []WeekSummary
+	for week, rows := range weeks {
+		summaries = append(summaries, newWeekSummary(week, rows))
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Start < summaries[j].Start })
+
+	return summaries
+}
+
+// Returns the grid for the week, with the totals for each row and day.
+func newWeekSummary(week string, rows map[string][]int) WeekSummary {
+	start, _ := time.ParseInLocation("2006-01-02", week, time.Local)
+
+	w := WeekSummary{Start: week}
+	for i := 0; i < 7; i++ {
+		w.Days = append(w.Days, start.AddDate(0, 0, i).Format("2006-01-02"))
+	}
+
+	dayTotals := make([]int, 7)
+	total := 0
+	for name, days := range rows {
+		row := GridRow{Name: name}
+		rowTotal := 0
+		for i, seconds := range days {
+			row.Days = append(row.Days, newDuration(seconds))
+			rowTotal += seconds
+			dayTotals[i] += seconds
+		}
+		row.Duration = newDuration(rowTotal)
+		total += rowTotal
+
+		w.Rows = append(w.Rows, row)
+	}
+	sort.Slice(w.Rows, func(i, j int) bool {
+		return strings.ToLower(w.Rows[i].Name) < strings.ToLower(w.Rows[j].Name)
+	})
+
+	for _, seconds := range dayTotals {
+		w.DayTotals = append(w.DayTotals, newDuration(seconds))
+	}
+	w.Duration = newDuration(total)
+
+	return w
+}
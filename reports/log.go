@@ -0,0 +1,161 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+	"github.com/mrcook/time_warrior/timeslip"
+)
+
+// LogEntry is an individual timeslip, as listed by the log.
+type LogEntry struct {
+	UUID        string     `json:"uuid"`
+	Project     string     `json:"project"`
+	Task        string     `json:"task,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Started     time.Time  `json:"started"`
+	Finished    *time.Time `json:"finished,omitempty"`
+	Status      string     `json:"status"`
+	Description string     `json:"description,omitempty"`
+	Duration
+
+	name string
+}
+
+// NewLogEntry returns the log entry for a timeslip.
+func NewLogEntry(slip *timeslip.Slip) LogEntry {
+	e := LogEntry{
+		UUID:        slip.UUID,
+		Project:     slip.Project,
+		Task:        slip.Task,
+		Tags:        slip.Tags,
+		Started:     time.Unix(int64(slip.Started), 0),
+		Status:      slip.Status,
+		Description: slip.Description,
+		Duration:    newDuration(slip.TotalTimeWorked()),
+		name:        slip.Name(),
+	}
+
+	if slip.Finished > 0 {
+		finished := time.Unix(int64(slip.Finished), 0)
+		e.Finished = &finished
+	}
+
+	return e
+}
+
+// Log is a chronological list of timeslips.
+type Log struct {
+	Entries    []LogEntry
+	ShowTotal  bool           // show the total time worked on all entries
+	TimePeriod *period.Period // when set, only the time worked within it is counted
+}
+
+// Add a timeslip to the log. With a time period, only the time worked within
+// the period is counted, as with the report command.
+func (l *Log) Add(slip *timeslip.Slip) {
+	e := NewLogEntry(slip)
+	if l.TimePeriod != nil && l.TimePeriod.IsSet() {
+		e.Duration = newDuration(slip.TimeWorkedBetween(periodBounds(l.TimePeriod)))
+	}
+	l.Entries = append(l.Entries, e)
+}
+
+// Sort the entries by their start time, oldest first, or newest first when
+// reversed. Only the limit most recent entries are kept, unless zero.
+func (l *Log) Sort(reverse bool, limit int) {
+	sort.SliceStable(l.Entries, func(i, j int) bool {
+		return l.Entries[i].Started.Before(l.Entries[j].Started)
+	})
+
+	if limit > 0 && len(l.Entries) > limit {
+		l.Entries = l.Entries[len(l.Entries)-limit:]
+	}
+
+	if reverse {
+		for i, j := 0, len(l.Entries)-1; i < j; i, j = i+1, j-1 {
+			l.Entries[i], l.Entries[j] = l.Entries[j], l.Entries[i]
+		}
+	}
+}
+
+// Render writes the log to w, as text or JSON.
+func (l *Log) Render(w io.Writer, f Format) error {
+	switch f {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if l.Entries == nil {
+			return encoder.Encode([]LogEntry{})
+		}
+		return encoder.Encode(l.Entries)
+	case FormatText:
+		l.writeText(w)
+		return nil
+	default:
+		return fmt.Errorf("the log can only be shown as text or json, got '%s'", f)
+	}
+}
+
+// Writes a line for each timeslip, with its start/end times, time worked,
+// status, short UUID, name, and description.
+func (l *Log) writeText(w io.Writer) {
+	if len(l.Entries) == 0 {
+		fmt.Fprintln(w, "No timeslips found.")
+		return
+	}
+
+	for _, e := range l.Entries {
+		name := e.name
+		for _, tag := range e.Tags {
+			name += " +" + tag
+		}
+
+		fmt.Fprintf(w, "%s  %s  %-9s  %-8s  %s", e.timeRange(), formatColumn(e.Seconds, false), e.Status, shortUUID(e.UUID), name)
+		if e.Description != "" {
+			fmt.Fprintf(w, "  %s", e.Description)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if l.ShowTotal {
+		total := 0
+		for _, e := range l.Entries {
+			total += e.Seconds
+		}
+		writeTextTotal(w, newDuration(total))
+	}
+}
+
+// Returns the start and end times, where the end time only shows the time of
+// day when on the same day as the start.
+func (e LogEntry) timeRange() string {
+	started := e.Started.Format(timeslip.DateFormat)
+
+	if e.Finished == nil {
+		return fmt.Sprintf("%s - %-*s", started, len(started), "...")
+	}
+
+	finished := e.Finished.Format(timeslip.DateFormat)
+	if sameDay(e.Started, *e.Finished) {
+		finished = e.Finished.Format("15:04")
+	}
+
+	return fmt.Sprintf("%s - %-*s", started, len(started), finished)
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+// Returns the first part of a UUID, which is enough to identify a timeslip.
+func shortUUID(uuid string) string {
+	if len(uuid) > 8 {
+		return uuid[:8]
+	}
+	return uuid
+}
@@ -0,0 +1,128 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+)
+
+func TestLog_Sort(t *testing.T) {
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.Local)
+
+	newLog := func() *Log {
+		l := &Log{}
+		l.Add(mustCompleted(t, "Beta.x", monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 2).Add(time.Hour)))
+		l.Add(mustCompleted(t, "Alpha.api", monday, monday.Add(time.Hour)))
+		l.Add(mustCompleted(t, "Alpha.docs", monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 1).Add(time.Hour)))
+		return l
+	}
+	names := func(l *Log) string {
+		var n []string
+		for _, e := range l.Entries {
+			n = append(n, e.name)
+		}
+		return strings.Join(n, ", ")
+	}
+
+	tests := map[string]struct {
+		reverse  bool
+		limit    int
+		expected string
+	}{
+		"oldest first":        {false, 0, "Alpha.api, Alpha.docs, Beta.x"},
+		"newest first":        {true, 0, "Beta.x, Alpha.docs, Alpha.api"},
+		"most recent":         {false, 2, "Alpha.docs, Beta.x"},
+		"most recent reverse": {true, 1, "Beta.x"},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			l := newLog()
+			l.Sort(test.reverse, test.limit)
+
+			if names(l) != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, names(l))
+			}
+		})
+	}
+}
+
+func TestLog_Render(t *testing.T) {
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.Local)
+	slip := mustCompleted(t, "Alpha.api", monday, monday.Add(2*time.Hour+15*time.Minute))
+
+	l := &Log{}
+	l.Add(slip)
+
+	var out bytes.Buffer
+	if err := l.Render(&out, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	expected := "2026-10-12 09:00 - 11:15                2h  15m  completed  " + slip.UUID[:8] + "  Alpha.api  work\n"
+	if out.String() != expected {
+		t.Errorf("unexpected log:\n%q\n%q", out.String(), expected)
+	}
+
+	out.Reset()
+	if err := l.Render(&out, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var entries []LogEntry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %s", err)
+	}
+	if len(entries) != 1 || entries[0].UUID != slip.UUID || entries[0].Seconds != 8100 || entries[0].Description != "work" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	if err := l.Render(&out, FormatCSV); err == nil {
+		t.Error("expected an error for the CSV format")
+	}
+}
+
+func TestLog_RenderTotal(t *testing.T) {
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.Local)
+
+	l := &Log{ShowTotal: true}
+	l.Add(mustCompleted(t, "Alpha.api", monday, monday.Add(time.Hour)))
+	l.Add(mustCompleted(t, "Beta.x", monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 1).Add(30*time.Minute)))
+
+	var out bytes.Buffer
+	if err := l.Render(&out, FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(out.String(), "===========\n   1h  30m\n") {
+		t.Errorf("expected the total time worked, got:\n%s", out.String())
+	}
+}
+
+func TestLog_TotalWithinPeriod(t *testing.T) {
+	p, err := period.Parse("2026-W42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// started on the Sunday of the previous week, finishing on the Monday
+	monday := time.Date(2026, time.October, 12, 0, 0, 0, 0, time.Local)
+	slip := mustCompleted(t, "Alpha.api", monday.Add(-time.Hour), monday.Add(2*time.Hour))
+
+	l := &Log{ShowTotal: true, TimePeriod: p}
+	l.Add(slip)
+
+	if l.Entries[0].Seconds != 2*60*60 {
+		t.Errorf("expected only the time worked within the period, got %d seconds", l.Entries[0].Seconds)
+	}
+
+	var out bytes.Buffer
+	if err := l.Render(&out, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(out.String(), "===========\n   2h   0m\n") {
+		t.Errorf("expected the total time worked within the period, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,287 @@
+package period
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An expression parser returns the period for a matching expression,
+// otherwise nil when the expression is not in its format.
+type expressionParser func(expr string, now time.Time) (*Period, error)
+
+var expressionParsers = []expressionParser{
+	parseNamedPeriod,
+	parseLastUnits,
+	parsePreviousUnits,
+	parseQuarter,
+	parseISOWeek,
+	parseDate,
+	parseWeekdayRange,
+	parseDateRange,
+}
+
+var (
+	lastUnitsFormat     = regexp.MustCompile(`^last (\d+) (day|week|month|year)s?$`)
+	previousUnitsFormat = regexp.MustCompile(`^(\d+)([dwmy])$`)
+	quarterFormat       = regexp.MustCompile(`^q([1-4]) (\d{4})$|^(\d{4})-q([1-4])$`)
+	isoWeekFormat       = regexp.MustCompile(`^(\d{4})-w(\d{2})$`)
+)
+
+// Parses a natural-language or ISO 8601 period expression.
+func parseExpression(expr string, now time.Time) (*Period, error) {
+	expr = strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+
+	for _, parse := range expressionParsers {
+		p, err := parse(expr, now)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			return p, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Returns a period with the label and time range.
+func newPeriod(label string, start, end time.Time) *Period {
+	return &Period{period: label, startTime: start, endTime: end}
+}
+
+// Parses the named periods, such as "today", "this week", and "last quarter".
+func parseNamedPeriod(expr string, now time.Time) (*Period, error) {
+	p := &Period{}
+
+	switch expr {
+	case "today":
+		return newPeriod("Today", p.BeginningOfDay(now), p.EndOfDay(now)), nil
+	case "yesterday":
+		t := p.Yesterday(now)
+		return newPeriod("Yesterday", p.BeginningOfDay(t), p.EndOfDay(t)), nil
+	case "this week":
+		return newPeriod("This Week", p.BeginningOfWeek(now), p.EndOfWeek(now)), nil
+	case "last week":
+		return newPeriod("Last Week", p.BeginningOfPreviousWeek(now), p.EndOfPreviousWeek(now)), nil
+	case "this month":
+		return newPeriod("This Month", p.BeginningOfMonth(now), p.EndOfMonth(now)), nil
+	case "last month":
+		return newPeriod("Last Month", p.BeginningOfPreviousMonth(now), p.EndOfPreviousMonth(now)), nil
+	case "this quarter":
+		return newPeriod("This Quarter", p.BeginningOfQuarter(now), p.EndOfQuarter(now)), nil
+	case "last quarter":
+		return newPeriod("Last Quarter", p.BeginningOfPreviousQuarter(now), p.EndOfPreviousQuarter(now)), nil
+	case "this fiscal quarter":
+		return newPeriod("This Fiscal Quarter", p.BeginningOfFiscalQuarter(now), p.EndOfFiscalQuarter(now)), nil
+	case "last fiscal quarter":
+		return newPeriod("Last Fiscal Quarter", p.BeginningOfPreviousFiscalQuarter(now), p.EndOfPreviousFiscalQuarter(now)), nil
+	case "this fiscal year":
+		return newPeriod("This Fiscal Year", p.BeginningOfFiscalYear(now), p.EndOfFiscalYear(now)), nil
+	case "last fiscal year":
+		return newPeriod("Last Fiscal Year", p.BeginningOfPreviousFiscalYear(now), p.EndOfPreviousFiscalYear(now)), nil
+	case "this year":
+		return newPeriod("This Year", p.BeginningOfYear(now), p.EndOfYear(now)), nil
+	case "last year":
+		return newPeriod("Last Year", p.BeginningOfPreviousYear(now), p.EndOfPreviousYear(now)), nil
+	}
+
+	return nil, nil
+}
+
+// Parses a rolling period ending today, e.g. "last 7 days" or "last 2 weeks".
+func parseLastUnits(expr string, now time.Time) (*Period, error) {
+	m := lastUnitsFormat.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, nil
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	if n < 1 {
+		return nil, fmt.Errorf("time period must be at least one %s, got '%s'", m[2], expr)
+	}
+
+	p := &Period{}
+	var start time.Time
+	switch m[2] {
+	case "day":
+		start = now.AddDate(0, 0, -n)
+	case "week":
+		start = now.AddDate(0, 0, -7*n)
+	case "month":
+		start = now.AddDate(0, -n, 0)
+	case "year":
+		start = now.AddDate(-n, 0, 0)
+	}
+	start = p.BeginningOfDay(start.AddDate(0, 0, 1))
+
+	unit := strings.ToUpper(m[2][:1]) + m[2][1:]
+	label := fmt.Sprintf("Last %d %ss", n, unit)
+	if n == 1 {
+		label = fmt.Sprintf("Last %s", unit)
+	}
+
+	return newPeriod(label, start, p.EndOfDay(now)), nil
+}
+
+// Parses the short codes for several whole days, weeks, months, or years
+// before the current one, e.g. "3w" is the three weeks before this week.
+func parsePreviousUnits(expr string, now time.Time) (*Period, error) {
+	m := previousUnitsFormat.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, nil
+	}
+
+	n, _ := strconv.Atoi(m[1])
+	if n < 1 {
+		return nil, fmt.Errorf("time period must be at least one unit, got '%s'", expr)
+	}
+
+	p := &Period{}
+	var start, end time.Time
+	var unit string
+	switch m[2] {
+	case "d":
+		unit = "Days"
+		end = p.EndOfDay(p.Yesterday(now))
+		start = p.BeginningOfDay(now.AddDate(0, 0, -n))
+	case "w":
+		unit = "Weeks"
+		end = p.EndOfPreviousWeek(now)
+		start = p.BeginningOfWeek(now.AddDate(0, 0, -7*n))
+	case "m":
+		unit = "Months"
+		end = p.EndOfPreviousMonth(now)
+		start = p.BeginningOfMonth(p.BeginningOfMonth(now).AddDate(0, -n, 0))
+	case "y":
+		unit = "Years"
+		end = p.EndOfPreviousYear(now)
+		start = p.BeginningOfYear(now).AddDate(-n, 0, 0)
+	}
+
+	return newPeriod(fmt.Sprintf("Previous %d %s", n, unit), start, end), nil
+}
+
+// Parses a calendar quarter, e.g. "Q3 2025" or "2025-Q3".
+func parseQuarter(expr string, now time.Time) (*Period, error) {
+	m := quarterFormat.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, nil
+	}
+
+	quarter, year := m[1], m[2]
+	if quarter == "" {
+		quarter, year = m[4], m[3]
+	}
+	q, _ := strconv.Atoi(quarter)
+	y, _ := strconv.Atoi(year)
+
+	p := &Period{}
+	start := time.Date(y, time.Month(3*(q-1)+1), 1, 0, 0, 0, 0, now.Location())
+
+	return newPeriod(fmt.Sprintf("Q%d %d", q, y), start, p.EndOfQuarter(start)), nil
+}
+
+// Parses an ISO 8601 week, e.g. "2026-W41". ISO weeks always start on a Monday.
+func parseISOWeek(expr string, now time.Time) (*Period, error) {
+	m := isoWeekFormat.FindStringSubmatch(expr)
+	if m == nil {
+		return nil, nil
+	}
+
+	year, _ := strconv.Atoi(m[1])
+	week, _ := strconv.Atoi(m[2])
+
+	// the first ISO week is the one containing January 4th
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, now.Location())
+	start := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+7*(week-1))
+
+	if y, w := start.ISOWeek(); week < 1 || y != year || w != week {
+		return nil, fmt.Errorf("invalid ISO week, got '%s'", strings.ToUpper(expr))
+	}
+
+	end := start.AddDate(0, 0, 7).Add(-time.Second)
+
+	return newPeriod(fmt.Sprintf("Week %d, %d", week, year), start, end), nil
+}
+
+// Parses a year, month, or day, e.g. "2026", "2026-09", or "2026-09-14".
+func parseDate(expr string, now time.Time) (*Period, error) {
+	p := &Period{}
+
+	if t, err := time.ParseInLocation("2006", expr, now.Location()); err == nil {
+		return newPeriod(t.Format("2006"), t, p.EndOfYear(t)), nil
+	}
+	if t, err := time.ParseInLocation("2006-01", expr, now.Location()); err == nil {
+		return newPeriod(t.Format("January 2006"), t, p.EndOfMonth(t)), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", expr, now.Location()); err == nil {
+		return newPeriod(t.Format("Monday, Jan 2, 2006"), t, p.EndOfDay(t)), nil
+	}
+
+	return nil, nil
+}
+
+// Parses a range of days in the current week, e.g. "monday..friday".
+func parseWeekdayRange(expr string, now time.Time) (*Period, error) {
+	parts := strings.Split(expr, "..")
+	if len(parts) != 2 {
+		return nil, nil
+	}
+
+	first, ok := weekdays[strings.TrimSpace(parts[0])]
+	if !ok {
+		return nil, nil
+	}
+	last, ok := weekdays[strings.TrimSpace(parts[1])]
+	if !ok {
+		return nil, nil
+	}
+
+	p := &Period{}
+	start := p.BeginningOfWeek(now)
+	from := start.AddDate(0, 0, daysFromWeekStart(first))
+	to := start.AddDate(0, 0, daysFromWeekStart(last))
+
+	if to.Before(from) {
+		return nil, fmt.Errorf("the week starts on %s, so %s can not be before %s", WeekStart, last, first)
+	}
+
+	return newPeriod(fmt.Sprintf("%s to %s", first, last), from, p.EndOfDay(to)), nil
+}
+
+// Parses a range of dates, e.g. "2026-09-01..2026-09-15".
+func parseDateRange(expr string, now time.Time) (*Period, error) {
+	parts := strings.Split(expr, "..")
+	if len(parts) != 2 {
+		return nil, nil
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(parts[0]), now.Location())
+	if err != nil {
+		return nil, nil
+	}
+	to, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(parts[1]), now.Location())
+	if err != nil {
+		return nil, nil
+	}
+
+	return Range(from, to)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// Returns the number of days the weekday is after the start of the week.
+func daysFromWeekStart(day time.Weekday) int {
+	return (int(day) - int(WeekStart) + 7) % 7
+}
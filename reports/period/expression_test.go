@@ -0,0 +1,90 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+)
+
+func TestParseAt_Expressions(t *testing.T) {
+	// Wednesday 14th October 2026
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		expr  string
+		label string
+		from  string
+		to    string
+	}{
+		{"today", "Today", "2026-10-14 00:00:00", "2026-10-14 23:59:59"},
+		{"yesterday", "Yesterday", "2026-10-13 00:00:00", "2026-10-13 23:59:59"},
+		{"this week", "This Week", "2026-10-12 00:00:00", "2026-10-18 23:59:59"},
+		{"last month", "Last Month", "2026-09-01 00:00:00", "2026-09-30 23:59:59"},
+		{"this quarter", "This Quarter", "2026-10-01 00:00:00", "2026-12-31 23:59:59"},
+		{"last quarter", "Last Quarter", "2026-07-01 00:00:00", "2026-09-30 23:59:59"},
+		{"last 7 days", "Last 7 Days", "2026-10-08 00:00:00", "2026-10-14 23:59:59"},
+		{"Last  2 Weeks", "Last 2 Weeks", "2026-10-01 00:00:00", "2026-10-14 23:59:59"},
+		{"last 1 month", "Last Month", "2026-09-15 00:00:00", "2026-10-14 23:59:59"},
+		{"3w", "Previous 3 Weeks", "2026-09-21 00:00:00", "2026-10-11 23:59:59"},
+		{"2d", "Previous 2 Days", "2026-10-12 00:00:00", "2026-10-13 23:59:59"},
+		{"2m", "Previous 2 Months", "2026-08-01 00:00:00", "2026-09-30 23:59:59"},
+		{"Q3 2025", "Q3 2025", "2025-07-01 00:00:00", "2025-09-30 23:59:59"},
+		{"2025-q1", "Q1 2025", "2025-01-01 00:00:00", "2025-03-31 23:59:59"},
+		{"2026-W41", "Week 41, 2026", "2026-10-05 00:00:00", "2026-10-11 23:59:59"},
+		{"2021-W01", "Week 1, 2021", "2021-01-04 00:00:00", "2021-01-10 23:59:59"},
+		{"2026-09", "September 2026", "2026-09-01 00:00:00", "2026-09-30 23:59:59"},
+		{"2025", "2025", "2025-01-01 00:00:00", "2025-12-31 23:59:59"},
+		{"2026-09-14", "Monday, Sep 14, 2026", "2026-09-14 00:00:00", "2026-09-14 23:59:59"},
+		{"monday..friday", "Monday to Friday", "2026-10-12 00:00:00", "2026-10-16 23:59:59"},
+		{"tue..wed", "Tuesday to Wednesday", "2026-10-13 00:00:00", "2026-10-14 23:59:59"},
+		{"2026-09-01..2026-09-15", "Date Range", "2026-09-01 00:00:00", "2026-09-15 23:59:59"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := period.ParseAt(tt.expr, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if p.Period() != tt.label {
+				t.Errorf("expected label '%s', got '%s'", tt.label, p.Period())
+			}
+			if from := p.From().Format("2006-01-02 15:04:05"); from != tt.from {
+				t.Errorf("expected from %s, got %s", tt.from, from)
+			}
+			if to := p.To().Format("2006-01-02 15:04:05"); to != tt.to {
+				t.Errorf("expected to %s, got %s", tt.to, to)
+			}
+		})
+	}
+}
+
+func TestParseAt_InvalidExpressions(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"2026-W54", "invalid ISO week, got '2026-W54'"},
+		{"2026-W00", "invalid ISO week, got '2026-W00'"},
+		{"friday..monday", "the week starts on Monday, so Monday can not be before Friday"},
+		{"last 0 days", "time period must be at least one day, got 'last 0 days'"},
+		{"0w", "time period must be at least one unit, got '0w'"},
+		{"Q5 2025", "unknown time period, got 'Q5 2025'. Expected: t, 1d, w, 1w, m, 1m, y, 1y, fq, 1fq, fy, 1fy, or an expression such as 'last 7 days'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := period.ParseAt(tt.expr, now)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Error() != tt.err {
+				t.Errorf("expected error '%s', got '%s'", tt.err, err)
+			}
+		})
+	}
+}
@@ -1,19 +1,38 @@
 package period
 
 import (
+	"fmt"
 	"time"
 )
 
+// WeekStart is the first day of the week, used for the weekly time periods.
+var WeekStart = time.Monday
+
+// FiscalYearStart is the first month of the fiscal year, used for the fiscal
+// quarter and year time periods.
+var FiscalYearStart = time.January
+
 type Period struct {
 	period    string
 	startTime time.Time
 	endTime   time.Time
 }
 
-func Parse(unit string) *Period {
-	p := &Period{period: unit}
+// Parse returns the time period for the unit code or expression, relative to
+// the current time. An empty unit is an unbounded period, covering all
+// timeslips.
+func Parse(unit string) (*Period, error) {
+	return ParseAt(unit, time.Now())
+}
+
+// ParseAt returns the time period for the unit code or expression, relative
+// to the given time.
+//
+// Along with the unit codes, expressions such as "last 7 days", "this quarter",
+// "Q3 2025", "2026-W41", "2026-09", "monday..friday", and "3w" are accepted.
+func ParseAt(unit string, now time.Time) (*Period, error) {
+	p := &Period{}
 
-	now := time.Now()
 	var start, end time.Time
 	var period string
 
@@ -51,16 +70,55 @@ func Parse(unit string) *Period {
 		period = "Last Year"
 		start = p.BeginningOfPreviousYear(now)
 		end = p.EndOfPreviousYear(now)
+	case "fq":
+		period = "This Fiscal Quarter"
+		start = p.BeginningOfFiscalQuarter(now)
+		end = p.EndOfFiscalQuarter(now)
+	case "fy":
+		period = "This Fiscal Year"
+		start = p.BeginningOfFiscalYear(now)
+		end = p.EndOfFiscalYear(now)
+	case "1fq":
+		period = "Last Fiscal Quarter"
+		start = p.BeginningOfPreviousFiscalQuarter(now)
+		end = p.EndOfPreviousFiscalQuarter(now)
+	case "1fy":
+		period = "Last Fiscal Year"
+		start = p.BeginningOfPreviousFiscalYear(now)
+		end = p.EndOfPreviousFiscalYear(now)
+	case "":
+		return p, nil
 	default:
-		start = p.BeginningOfDay(now)
-		end = p.EndOfDay(now)
+		expr, err := parseExpression(unit, now)
+		if err != nil {
+			return nil, err
+		}
+		if expr == nil {
+			return nil, fmt.Errorf("unknown time period, got '%s'. Expected: t, 1d, w, 1w, m, 1m, y, 1y, fq, 1fq, fy, 1fy, or an expression such as 'last 7 days'", unit)
+		}
+		return expr, nil
 	}
 
 	p.period = period
 	p.startTime = start
 	p.endTime = end
 
-	return p
+	return p, nil
+}
+
+// Range returns the time period from the beginning of the first day to the
+// end of the last day.
+func Range(from, to time.Time) (*Period, error) {
+	p := &Period{period: "Date Range"}
+
+	p.startTime = p.BeginningOfDay(from)
+	p.endTime = p.EndOfDay(to)
+
+	if p.endTime.Before(p.startTime) {
+		return nil, fmt.Errorf("the end date can not be before the start date")
+	}
+
+	return p, nil
 }
 
 func (p Period) Period() string {
@@ -98,7 +156,7 @@ func (p Period) lastWeek(t time.Time) time.Time {
 }
 
 func (p Period) BeginningOfWeek(t time.Time) time.Time {
-	for t.Weekday() != time.Monday {
+	for t.Weekday() != WeekStart {
 		t = t.AddDate(0, 0, -1)
 	}
 	year, month, day := t.Date()
@@ -136,6 +194,23 @@ func (p Period) EndOfPreviousMonth(t time.Time) time.Time {
 	return p.BeginningOfMonth(t).Add(-time.Second)
 }
 
+func (p Period) BeginningOfQuarter(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, t.Location())
+}
+
+func (p Period) EndOfQuarter(t time.Time) time.Time {
+	return p.BeginningOfQuarter(t).AddDate(0, 3, 0).Add(-time.Second)
+}
+
+func (p Period) BeginningOfPreviousQuarter(t time.Time) time.Time {
+	return p.BeginningOfQuarter(t).AddDate(0, -3, 0)
+}
+
+func (p Period) EndOfPreviousQuarter(t time.Time) time.Time {
+	return p.BeginningOfQuarter(t).Add(-time.Second)
+}
+
 func (p Period) BeginningOfYear(t time.Time) time.Time {
 	y, _, _ := t.Date()
 	return time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location())
@@ -153,3 +228,41 @@ func (p Period) BeginningOfPreviousYear(t time.Time) time.Time {
 func (p Period) EndOfPreviousYear(t time.Time) time.Time {
 	return p.BeginningOfPreviousYear(t).AddDate(1, 0, 0).Add(-time.Second)
 }
+
+func (p Period) BeginningOfFiscalQuarter(t time.Time) time.Time {
+	start := p.BeginningOfFiscalYear(t)
+	months := (int(t.Month()) - int(FiscalYearStart) + 12) % 12
+	return start.AddDate(0, months-months%3, 0)
+}
+
+func (p Period) EndOfFiscalQuarter(t time.Time) time.Time {
+	return p.BeginningOfFiscalQuarter(t).AddDate(0, 3, 0).Add(-time.Second)
+}
+
+func (p Period) BeginningOfPreviousFiscalQuarter(t time.Time) time.Time {
+	return p.BeginningOfFiscalQuarter(t).AddDate(0, -3, 0)
+}
+
+func (p Period) EndOfPreviousFiscalQuarter(t time.Time) time.Time {
+	return p.BeginningOfFiscalQuarter(t).Add(-time.Second)
+}
+
+func (p Period) BeginningOfFiscalYear(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	if m < FiscalYearStart {
+		y--
+	}
+	return time.Date(y, FiscalYearStart, 1, 0, 0, 0, 0, t.Location())
+}
+
+func (p Period) EndOfFiscalYear(t time.Time) time.Time {
+	return p.BeginningOfFiscalYear(t).AddDate(1, 0, 0).Add(-time.Second)
+}
+
+func (p Period) BeginningOfPreviousFiscalYear(t time.Time) time.Time {
+	return p.BeginningOfFiscalYear(t).AddDate(-1, 0, 0)
+}
+
+func (p Period) EndOfPreviousFiscalYear(t time.Time) time.Time {
+	return p.BeginningOfFiscalYear(t).Add(-time.Second)
+}
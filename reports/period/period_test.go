@@ -8,7 +8,7 @@ import (
 )
 
 func TestPeriodToday(t *testing.T) {
-	p := period.Parse("t")
+	p, _ := period.Parse("t")
 
 	now := time.Now()
 	bod := p.BeginningOfDay(now)
@@ -25,26 +25,55 @@ func TestPeriodToday(t *testing.T) {
 	}
 }
 
-func TestPeriodDefault(t *testing.T) {
-	p := period.Parse("bad time unit")
+func TestPeriodAll(t *testing.T) {
+	p, err := period.Parse("")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
 
-	now := time.Now()
-	bod := p.BeginningOfDay(now)
-	eod := p.EndOfDay(now)
+	if p.IsSet() {
+		t.Errorf("Expected an unbounded period, got %s", p.Period())
+	}
+}
 
-	if p.From().Unix() != bod.Unix() {
-		t.Errorf("Expected default to be start of today: %d, got %d", bod.Unix(), p.From().Unix())
+func TestPeriodUnknown(t *testing.T) {
+	_, err := period.Parse("bad time unit")
+	if err == nil {
+		t.Fatal("Expected an error for an unknown time period")
 	}
-	if p.To().Unix() != eod.Unix() {
-		t.Errorf("Expected default to be end of today: %d, got %d", eod.Unix(), p.To().Unix())
+
+	expected := "unknown time period, got 'bad time unit'. Expected: t, 1d, w, 1w, m, 1m, y, 1y, fq, 1fq, fy, 1fy, or an expression such as 'last 7 days'"
+	if err.Error() != expected {
+		t.Errorf("Expected error '%s', got '%s'", expected, err)
 	}
-	if p.Period() != "" {
-		t.Errorf("Expected empty period string, got %s", p.Period())
+}
+
+func TestRange(t *testing.T) {
+	from, _ := time.Parse("2006-01-02 15:04:05", "2026-09-01 10:30:00")
+	to, _ := time.Parse("2006-01-02 15:04:05", "2026-09-15 08:00:00")
+
+	p, err := period.Range(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if p.From().Format("2006-01-02 15:04:05") != "2026-09-01 00:00:00" {
+		t.Errorf("Expected start of the first day, got %s", p.From())
+	}
+	if p.To().Format("2006-01-02 15:04:05") != "2026-09-15 23:59:59" {
+		t.Errorf("Expected end of the last day, got %s", p.To())
+	}
+	if !p.IsSet() {
+		t.Error("Expected the period to be set")
+	}
+
+	if _, err := period.Range(to, from); err == nil {
+		t.Error("Expected an error when the end is before the start")
 	}
 }
 
 func TestPeriodYesterday(t *testing.T) {
-	p := period.Parse("1d")
+	p, _ := period.Parse("1d")
 
 	yesterday := p.Yesterday(time.Now())
 	boy := p.BeginningOfDay(yesterday)
@@ -62,7 +91,7 @@ func TestPeriodYesterday(t *testing.T) {
 }
 
 func TestPeriodWeekToDate(t *testing.T) {
-	p := period.Parse("w")
+	p, _ := period.Parse("w")
 
 	now := time.Now()
 	bow := p.BeginningOfWeek(now)
@@ -80,7 +109,7 @@ func TestPeriodWeekToDate(t *testing.T) {
 }
 
 func TestPeriodMonthToDate(t *testing.T) {
-	p := period.Parse("m")
+	p, _ := period.Parse("m")
 
 	now := time.Now()
 	bow := p.BeginningOfMonth(now)
@@ -98,7 +127,7 @@ func TestPeriodMonthToDate(t *testing.T) {
 }
 
 func TestPeriodYearToDate(t *testing.T) {
-	p := period.Parse("y")
+	p, _ := period.Parse("y")
 
 	now := time.Now()
 	bow := p.BeginningOfYear(now)
@@ -116,7 +145,7 @@ func TestPeriodYearToDate(t *testing.T) {
 }
 
 func TestPeriodLastWeek(t *testing.T) {
-	p := period.Parse("1w")
+	p, _ := period.Parse("1w")
 
 	now := time.Now()
 	bow := p.BeginningOfPreviousWeek(now)
@@ -134,7 +163,7 @@ func TestPeriodLastWeek(t *testing.T) {
 }
 
 func TestPeriodLastMonth(t *testing.T) {
-	p := period.Parse("1m")
+	p, _ := period.Parse("1m")
 
 	now := time.Now()
 	bow := p.BeginningOfPreviousMonth(now)
@@ -152,7 +181,7 @@ func TestPeriodLastMonth(t *testing.T) {
 }
 
 func TestPeriodLastYear(t *testing.T) {
-	p := period.Parse("1y")
+	p, _ := period.Parse("1y")
 
 	now := time.Now()
 	bow := p.BeginningOfPreviousYear(now)
@@ -170,7 +199,7 @@ func TestPeriodLastYear(t *testing.T) {
 }
 
 func TestBeginningOfDay(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-05 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2019-01-05 00:00:00")
@@ -183,7 +212,7 @@ func TestBeginningOfDay(t *testing.T) {
 }
 
 func TestEndOfDay(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-04 08:11:43")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2019-01-04 23:59:59")
@@ -196,7 +225,7 @@ func TestEndOfDay(t *testing.T) {
 }
 
 func TestYesterday(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-04-01 08:11:43")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2019-03-31 08:11:43")
@@ -209,7 +238,7 @@ func TestYesterday(t *testing.T) {
 }
 
 func TestBeginningOfWeek(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	// Friday 4th January
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-04 14:24:01")
@@ -224,8 +253,27 @@ func TestBeginningOfWeek(t *testing.T) {
 	}
 }
 
+func TestBeginningOfWeek_WeekStart(t *testing.T) {
+	period.WeekStart = time.Sunday
+	defer func() { period.WeekStart = time.Monday }()
+
+	p, _ := period.Parse("")
+
+	// Friday 4th January
+	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-04 14:24:01")
+
+	// Sunday 30th December
+	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-12-30 00:00:00")
+
+	bow := p.BeginningOfWeek(timeNow)
+
+	if bow.String() != actual.String() {
+		t.Errorf("Expected beginning of week, got %s", bow.String())
+	}
+}
+
 func TestEndOfWeek(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	// Friday 4th January
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-04 14:24:01")
@@ -241,7 +289,7 @@ func TestEndOfWeek(t *testing.T) {
 }
 
 func TestBeginningOfPreviousWeek(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	// Wednesday 12th September
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2018-09-12 12:02:34")
@@ -257,7 +305,7 @@ func TestBeginningOfPreviousWeek(t *testing.T) {
 }
 
 func TestEndOfPreviousWeek(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	// Wednesday 12th September
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2018-09-12 12:02:34")
@@ -273,7 +321,7 @@ func TestEndOfPreviousWeek(t *testing.T) {
 }
 
 func TestBeginningOfMonth(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2018-10-10 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-10-01 00:00:00")
@@ -285,7 +333,7 @@ func TestBeginningOfMonth(t *testing.T) {
 	}
 }
 func TestEndOfMonth(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2019-01-31 23:59:59")
@@ -298,7 +346,7 @@ func TestEndOfMonth(t *testing.T) {
 }
 
 func TestBeginningOfPreviousMonth(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-12-01 00:00:00")
@@ -311,7 +359,7 @@ func TestBeginningOfPreviousMonth(t *testing.T) {
 }
 
 func TestEndOfPreviousMonth(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-12-31 23:59:59")
@@ -324,7 +372,7 @@ func TestEndOfPreviousMonth(t *testing.T) {
 }
 
 func TestBeginningOfYear(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2019-01-01 00:00:00")
@@ -337,7 +385,7 @@ func TestBeginningOfYear(t *testing.T) {
 }
 
 func TestEndOfYear(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2018-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-12-31 23:59:59")
@@ -350,7 +398,7 @@ func TestEndOfYear(t *testing.T) {
 }
 
 func TestBeginningOfPreviousYear(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-01-01 00:00:00")
@@ -363,7 +411,7 @@ func TestBeginningOfPreviousYear(t *testing.T) {
 }
 
 func TestEndOfPreviousYear(t *testing.T) {
-	p := period.Parse("")
+	p, _ := period.Parse("")
 
 	timeNow, _ := time.Parse("2006-01-02 15:04:05", "2019-01-09 14:24:01")
 	actual, _ := time.Parse("2006-01-02 15:04:05", "2018-12-31 23:59:59")
@@ -374,3 +422,61 @@ func TestEndOfPreviousYear(t *testing.T) {
 		t.Errorf("Expected end of previous year, got %s", eoy.String())
 	}
 }
+
+func TestFiscalPeriods(t *testing.T) {
+	period.FiscalYearStart = time.April
+	defer func() { period.FiscalYearStart = time.January }()
+
+	// Wednesday 14th January 2026
+	now := time.Date(2026, time.January, 14, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		unit  string
+		label string
+		from  string
+		to    string
+	}{
+		{"fq", "This Fiscal Quarter", "2026-01-01 00:00:00", "2026-03-31 23:59:59"},
+		{"1fq", "Last Fiscal Quarter", "2025-10-01 00:00:00", "2025-12-31 23:59:59"},
+		{"fy", "This Fiscal Year", "2025-04-01 00:00:00", "2026-03-31 23:59:59"},
+		{"1fy", "Last Fiscal Year", "2024-04-01 00:00:00", "2025-03-31 23:59:59"},
+		{"this fiscal quarter", "This Fiscal Quarter", "2026-01-01 00:00:00", "2026-03-31 23:59:59"},
+		{"last fiscal year", "Last Fiscal Year", "2024-04-01 00:00:00", "2025-03-31 23:59:59"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.unit, func(t *testing.T) {
+			p, err := period.ParseAt(tt.unit, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if p.Period() != tt.label {
+				t.Errorf("expected label '%s', got '%s'", tt.label, p.Period())
+			}
+			if from := p.From().Format("2006-01-02 15:04:05"); from != tt.from {
+				t.Errorf("expected from %s, got %s", tt.from, from)
+			}
+			if to := p.To().Format("2006-01-02 15:04:05"); to != tt.to {
+				t.Errorf("expected to %s, got %s", tt.to, to)
+			}
+		})
+	}
+}
+
+func TestFiscalQuarter_MidYear(t *testing.T) {
+	period.FiscalYearStart = time.April
+	defer func() { period.FiscalYearStart = time.January }()
+
+	p, _ := period.Parse("")
+
+	// Friday 14th August 2026, in the second quarter of the fiscal year
+	timeNow := time.Date(2026, time.August, 14, 10, 0, 0, 0, time.Local)
+
+	if bq := p.BeginningOfFiscalQuarter(timeNow).Format("2006-01-02"); bq != "2026-07-01" {
+		t.Errorf("Expected beginning of fiscal quarter, got %s", bq)
+	}
+	if eq := p.EndOfFiscalQuarter(timeNow).Format("2006-01-02"); eq != "2026-09-30" {
+		t.Errorf("Expected end of fiscal quarter, got %s", eq)
+	}
+}
@@ -1,8 +1,6 @@
 package reports
 
 import (
-	"bufio"
-	"io"
 	"sort"
 	"strings"
 
@@ -12,8 +10,11 @@ import (
 type project struct {
 	name            string
 	timePeriod      *period.Period
+	filter          Filter
 	totalTimeWorked int
+	slips           []*task
 	tasks           map[string]*task
+	tags            map[string]int
 	scanErrors      []scanError
 }
 
@@ -23,31 +24,28 @@ type scanError struct {
 }
 
 // Initializes a new project.
-func newProject(p *period.Period) *project {
+func newProject(p *period.Period, f Filter) *project {
 	return &project{
 		timePeriod: p,
+		filter:     f,
 		tasks:      make(map[string]*task),
+		tags:       make(map[string]int),
 	}
 }
 
-// Process the timeslips form the given file. Scanner errors are returned
+// Process the timeslips given by the reader. Read errors are returned
 // directly, project/task errors are recorded for later use.
-func (p *project) process(file io.Reader) error {
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		err := p.processSlip(scanner.Bytes())
-		if err != nil {
-			p.scanErrors = append(p.scanErrors, scanError{scanner: err, timeslip: scanner.Text()})
+func (p *project) process(read SlipReader) error {
+	return read(func(data []byte) error {
+		if err := p.processSlip(data); err != nil {
+			p.scanErrors = append(p.scanErrors, scanError{scanner: err, timeslip: string(data)})
 		}
-	}
-	return scanner.Err()
+		return nil
+	})
 }
 
-// Process a timeslip as a task and add the time worked to the project total.
-//
-// If a task name already exists then the time worked is added to the current
-// entry, otherwise the timeslip is saved as a new entry. A new project is
-// given the name as found in the first task.
+// Process a timeslip as a task, which is saved for calculating the time
+// worked. A new project is given the name as found in the first task.
 func (p *project) processSlip(data []byte) error {
 	t, err := newTask(data)
 	if err != nil {
@@ -59,32 +57,133 @@ func (p *project) processSlip(data []byte) error {
 		p.name = t.project
 	}
 
-	// skip processing if the task was done outside the desired time period
-	if p.timePeriod.IsSet() && !p.withinTimePeriod(t) {
-		return nil
+	// only the time worked during the desired time period is counted, and
+	// the task is skipped if none of the work was done within it
+	if p.timePeriod.IsSet() {
+		t.timeWorked = p.timeWorkedWithinPeriod(t)
+		if t.timeWorked == 0 {
+			return nil
+		}
 	}
 
-	// add as a new task if its name has not been seen previously,
-	// otherwise, add its worked time to the current task.
-	if _, ok := p.tasks[t.name]; !ok {
-		p.tasks[t.name] = t
-	} else {
-		p.tasks[t.name].timeWorked += t.timeWorked
+	// skip processing if the task tags do not match the filter
+	if !p.filter.Matches(t.tags) {
+		return nil
 	}
 
-	p.totalTimeWorked += t.timeWorked
+	p.slips = append(p.slips, t)
 
 	return nil
 }
 
-// Check if the task was worked on during the desired time period.
+// Calculate the time worked for each task and tag, and the project total,
+// rounding the time worked as per the policy.
 //
-// For simplicity, we only check that the finished time happened within the
-// from/to time period, but what if 95% of the work was done outside of this?
-// Perhaps a better solution would be to consider that at least 50% of the work
-// was completed within the desired period.
-func (p project) withinTimePeriod(t *task) bool {
-	return t.finished >= int(p.timePeriod.From().Unix()) && t.finished <= int(p.timePeriod.To().Unix())
+// A task is added for each task name, with the time worked for all timeslips
+// of that name. The project total is the sum of all tasks.
+func (p *project) calculate(r Rounding) {
+	p.tasks = make(map[string]*task)
+	p.totalTimeWorked = 0
+
+	// rounding per day needs the time worked on each day, as in a timesheet
+	slips := p.slips
+	if r.Scope == RoundDay {
+		slips = p.slipsPerDay()
+	}
+
+	taskTotals := r.totals(slips, func(t *task) []string { return []string{t.name} })
+	for _, t := range p.slips {
+		if _, ok := p.tasks[t.name]; !ok {
+			p.tasks[t.name] = &task{name: t.name, project: t.project, timeWorked: taskTotals[t.name]}
+			p.totalTimeWorked += taskTotals[t.name]
+		}
+	}
+
+	p.tags = r.totals(slips, func(t *task) []string {
+		if len(t.tags) == 0 {
+			return []string{untagged}
+		}
+		return t.tags
+	})
+}
+
+// Calculate the time worked on each task per day, as `day -> task -> seconds`,
+// rounding the time worked as per the policy. A timeslip worked over several
+// days has its time split across each of them.
+func (p *project) dailyTotals(r Rounding) map[string]map[string]int {
+	days := make(map[string][]*task)
+	for _, d := range p.slipsPerDay() {
+		days[d.day()] = append(days[d.day()], d)
+	}
+
+	totals := make(map[string]map[string]int)
+	for day, tasks := range days {
+		totals[day] = r.totals(tasks, func(t *task) []string { return []string{t.name} })
+	}
+
+	return totals
+}
+
+// Returns the timeslips split into a task for each day they were worked on,
+// within the time period.
+func (p *project) slipsPerDay() []*task {
+	from, to := periodBounds(p.timePeriod)
+
+	var days []*task
+	for _, t := range p.slips {
+		days = append(days, t.days(from, to)...)
+	}
+	return days
+}
+
+// Returns the descriptions of the work done on a task, in the order it was
+// finished. Rounding per timeslip is applied to the time worked. When deduped,
+// repeated descriptions are combined, ignoring case and surrounding spaces.
+func (p *project) descriptions(name string, r Rounding, dedupe bool) []DescriptionSummary {
+	var slips []*task
+	for _, t := range p.slips {
+		if t.name == name && strings.TrimSpace(t.slip.Description) != "" {
+			slips = append(slips, t)
+		}
+	}
+	sort.SliceStable(slips, func(i, j int) bool { return slips[i].finished < slips[j].finished })
+
+	var descriptions []DescriptionSummary
+	seen := make(map[string]int)
+
+	for _, t := range slips {
+		seconds := t.timeWorked
+		if r.IsSet() && r.Scope == RoundSlip {
+			seconds = r.round(seconds)
+		}
+		text := strings.TrimSpace(t.slip.Description)
+
+		key := strings.ToLower(text)
+		if i, ok := seen[key]; ok && dedupe {
+			d := &descriptions[i]
+			d.Date = t.day()
+			d.Count++
+			d.Duration = newDuration(d.Seconds + seconds)
+			continue
+		}
+
+		seen[key] = len(descriptions)
+		d := DescriptionSummary{Date: t.day(), Text: text, Duration: newDuration(seconds)}
+		if dedupe {
+			d.Count = 1
+		}
+		descriptions = append(descriptions, d)
+	}
+
+	return descriptions
+}
+
+// Returns the time worked on the task during the desired time period, from
+// the overlap of its work intervals with the period. A timeslip started on a
+// Friday evening and completed on the Monday is split across both weeks.
+func (p project) timeWorkedWithinPeriod(t *task) int {
+	from, to := periodBounds(p.timePeriod)
+	return t.slip.TimeWorkedBetween(from, to)
 }
 
 // Returns an array of all tasks, sorted by name.
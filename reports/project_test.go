@@ -0,0 +1,161 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/status"
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+func TestProject_ProportionalAttribution(t *testing.T) {
+	// Friday 9th October, with the work finished on Monday
+	friday := time.Date(2026, time.October, 9, 20, 0, 0, 0, time.Local)
+	monday := time.Date(2026, time.October, 12, 10, 0, 0, 0, time.Local)
+
+	slip := timeslip.Slip{
+		Project:  "Warrior",
+		Task:     "Api",
+		Started:  int(friday.Unix()),
+		Finished: int(monday.Unix()),
+		Worked:   3 * 60 * 60,
+		Status:   status.Completed,
+		Segments: []timeslip.Segment{
+			{Start: int(friday.Unix()), End: int(friday.Unix()) + 2*60*60},
+			{Start: int(monday.Unix()) - 60*60, End: int(monday.Unix())},
+		},
+	}
+
+	tests := map[string]struct {
+		period string
+		worked int
+	}{
+		"first week":  {"2026-W41", 2 * 60 * 60},
+		"second week": {"2026-W42", 60 * 60},
+		"other week":  {"2026-W43", 0},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			p, err := period.Parse(test.period)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			proj := newProject(p, Filter{})
+			if err := proj.processSlip(slip.ToJson()); err != nil {
+				t.Fatal(err)
+			}
+			proj.calculate(Rounding{})
+
+			if proj.totalTimeWorked != test.worked {
+				t.Errorf("expected %d seconds, got %d", test.worked, proj.totalTimeWorked)
+			}
+		})
+	}
+}
+
+func TestProject_RoundPerDayOverMidnight(t *testing.T) {
+	p, err := period.Parse("2026-W42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10 minutes worked on the Thursday, and 20 minutes on the Friday
+	late := time.Date(2026, time.October, 15, 23, 50, 0, 0, time.Local)
+	slip, err := timeslip.NewCompleted("Warrior.api", late, late.Add(30*time.Minute), "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slip.Tags = []string{"billable"}
+
+	proj := newProject(p, Filter{})
+	if err := proj.processSlip(slip.ToJson()); err != nil {
+		t.Fatal(err)
+	}
+
+	r := Rounding{Mode: worked.RoundUp, Increment: 15 * 60, Scope: RoundDay}
+	proj.calculate(r)
+
+	if proj.totalTimeWorked != 45*60 {
+		t.Errorf("expected 45 minutes, rounded up on each day, got %d seconds", proj.totalTimeWorked)
+	}
+	if proj.tags["billable"] != 45*60 {
+		t.Errorf("expected 45 minutes for the tag, got %d seconds", proj.tags["billable"])
+	}
+
+	daily := 0
+	for _, tasks := range proj.dailyTotals(r) {
+		for _, seconds := range tasks {
+			daily += seconds
+		}
+	}
+	if daily != proj.totalTimeWorked {
+		t.Errorf("expected the daily totals to match the project total, got %d seconds", daily)
+	}
+}
+
+func TestReport_PendingTimeWorkedWithinPeriod(t *testing.T) {
+	now := time.Now()
+
+	pending := timeslip.Slip{
+		Project:  "Warrior",
+		Started:  int(now.Unix()) - 60*60,
+		Modified: int(now.Unix()) - 60*60,
+		Status:   status.Started,
+	}
+
+	recent, _ := period.Parse("last 2 days")
+	report := New(recent)
+	report.PendingTimeslip = pending
+	if worked := report.pendingTimeWorked(); worked < 59*60 || worked > 60*60 {
+		t.Errorf("expected about an hour worked recently, got %d seconds", worked)
+	}
+
+	lastYear, _ := period.Parse("1y")
+	report = New(lastYear)
+	report.PendingTimeslip = pending
+	if worked := report.pendingTimeWorked(); worked != 0 {
+		t.Errorf("expected no time worked last year, got %d seconds", worked)
+	}
+}
+
+func TestProject_Descriptions(t *testing.T) {
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.Local)
+
+	proj := newProject(&period.Period{}, Filter{})
+	for i, description := range []string{"Code review", "Fixed the login bug", " code review", ""} {
+		from := monday.AddDate(0, 0, i)
+		slip, err := timeslip.NewCompleted("Warrior.api", from, from.Add(30*time.Minute), description)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proj.processSlip(slip.ToJson()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("each description", func(t *testing.T) {
+		descriptions := proj.descriptions("api", Rounding{}, false)
+		if len(descriptions) != 3 {
+			t.Fatalf("expected 3 descriptions, got %+v", descriptions)
+		}
+		if descriptions[0].Date != "2026-10-12" || descriptions[0].Text != "Code review" || descriptions[0].Seconds != 30*60 {
+			t.Errorf("unexpected description: %+v", descriptions[0])
+		}
+	})
+
+	t.Run("deduped", func(t *testing.T) {
+		descriptions := proj.descriptions("api", Rounding{}, true)
+		if len(descriptions) != 2 {
+			t.Fatalf("expected 2 descriptions, got %+v", descriptions)
+		}
+
+		review := descriptions[0]
+		if review.Count != 2 || review.Seconds != 60*60 || review.Date != "2026-10-14" {
+			t.Errorf("expected the code reviews to be combined, got %+v", review)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is an output format for a report.
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat returns the report Format for the name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatText, FormatJSON, FormatCSV, FormatMarkdown:
+		return f, nil
+	case "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown report format, got '%s'. Expected: text, json, csv, or markdown", name)
+	}
+}
+
+// Render writes the report summary to w in the given format.
+func (s *Summary) Render(w io.Writer, f Format) error {
+	switch f {
+	case FormatJSON:
+		return s.writeJSON(w)
+	case FormatCSV:
+		return s.writeCSV(w)
+	case FormatMarkdown:
+		return s.writeMarkdown(w)
+	default:
+		return s.writeText(w)
+	}
+}
+
+// Returns the dates of the time period, e.g. `Jan 2, 2006 to Jan 8, 2006`.
+func (p PeriodSummary) formattedDates() string {
+	from := p.From.Format("Jan 2, 2006")
+	to := p.To.Format("Jan 2, 2006")
+
+	if from == to {
+		return from
+	}
+	return fmt.Sprintf("%s to %s", from, to)
+}
+
+// Returns a day, e.g. `2006-01-02`, in a readable format.
+func formatDate(day string) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return day
+	}
+	return t.Format("Monday, Jan 2, 2006")
+}
+
+// Returns the description, with the number of times it was repeated.
+func (d DescriptionSummary) text() string {
+	if d.Count > 1 {
+		return fmt.Sprintf("%s (x%d)", d.Text, d.Count)
+	}
+	return d.Text
+}
+
+// Returns the pending task name, with a trailing space when set.
+func (p PendingSummary) taskPrefix() string {
+	if p.Task == "" {
+		return ""
+	}
+	return p.Task + " "
+}
+
+// Returns the pending timeslip tags as a list, or untagged.
+func (p PendingSummary) tagNames() string {
+	if len(p.Tags) == 0 {
+		return untagged
+	}
+	return strings.Join(p.Tags, ", ")
+}
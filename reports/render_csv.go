@@ -0,0 +1,122 @@
+package reports
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+)
+
+var csvHeader = []string{"project", "task", "tag", "seconds", "duration", "period_from", "period_to", "pending"}
+
+var csvDayHeader = []string{"date", "project", "task", "seconds", "duration", "pending"}
+
+// Writes the report as CSV, with a row for each task, or each tag when grouped
+// by tag, followed by the pending timeslip. The report total is not included,
+// as it is easily summed in a spreadsheet.
+func (s *Summary) writeCSV(w io.Writer) error {
+	if s.grid {
+		return s.writeGridCSV(w)
+	}
+	if s.groupByDay {
+		return s.writeDaysCSV(w)
+	}
+
+	out := csv.NewWriter(w)
+
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+
+	if s.groupByTag {
+		for _, t := range s.Tags {
+			if err := out.Write(s.csvRow("", "", t.Name, t.Duration, false)); err != nil {
+				return err
+			}
+		}
+	} else {
+		for _, p := range s.Projects {
+			for _, t := range p.Tasks {
+				if err := out.Write(s.csvRow(p.Name, t.Name, "", t.Duration, false)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if s.Pending != nil {
+		row := s.csvRow(s.Pending.Project, s.Pending.Task, strings.Join(s.Pending.Tags, ";"), s.Pending.Duration, true)
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func (s *Summary) csvRow(project, task, tag string, d Duration, pending bool) []string {
+	var from, to string
+	if s.Period != nil {
+		from = s.Period.From.Format(time.RFC3339)
+		to = s.Period.To.Format(time.RFC3339)
+	}
+
+	return []string{project, task, tag, strconv.Itoa(d.Seconds), d.Duration, from, to, strconv.FormatBool(pending)}
+}
+
+// Writes the timesheet as CSV, with a row for each task per day, including
+// the pending timeslip.
+func (s *Summary) writeDaysCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	if err := out.Write(csvDayHeader); err != nil {
+		return err
+	}
+
+	for _, d := range s.Days {
+		for _, t := range d.Tasks {
+			row := []string{d.Date, t.Project, t.Task, strconv.Itoa(t.Seconds), t.Duration.Duration, strconv.FormatBool(t.Pending)}
+			if err := out.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// Writes the timesheet grid as CSV, with a row of the seconds worked on each
+// project, or task, per day of each week. The day columns are ordered from
+// the first day of the week.
+func (s *Summary) writeGridCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := []string{"week", "name"}
+	for i := 0; i < 7; i++ {
+		day := time.Weekday((int(period.WeekStart) + i) % 7)
+		header = append(header, strings.ToLower(day.String()[:3]))
+	}
+	if err := out.Write(append(header, "total")); err != nil {
+		return err
+	}
+
+	for _, week := range s.Weeks {
+		for _, row := range week.Rows {
+			record := []string{week.Start, row.Name}
+			for _, d := range row.Days {
+				record = append(record, strconv.Itoa(d.Seconds))
+			}
+			if err := out.Write(append(record, strconv.Itoa(row.Seconds))); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,13 @@
+package reports
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Writes the report summary as indented JSON.
+func (s *Summary) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
@@ -0,0 +1,154 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Writes the report as a Markdown table, with the same rows as the text report.
+func (s *Summary) writeMarkdown(w io.Writer) error {
+	title := "Time Report"
+	column := "Project"
+	if s.groupByDay {
+		title = "Timesheet"
+		column = "Task"
+	} else if s.groupByTag {
+		column = "Tag"
+	} else if len(s.Projects) == 1 {
+		title = fmt.Sprintf("Project: %s", s.Projects[0].Name)
+		column = "Task"
+	}
+
+	fmt.Fprintf(w, "## %s\n\n", markdownEscape(title))
+	if s.Period != nil {
+		fmt.Fprintf(w, "**Time Period:** %s (%s)  \n", s.Period.Name, s.Period.formattedDates())
+	}
+	if s.Rounding != "" {
+		fmt.Fprintf(w, "**Rounding:** %s  \n", s.Rounding)
+	}
+	if s.Period != nil || s.Rounding != "" {
+		fmt.Fprintln(w)
+	}
+
+	if s.grid {
+		s.writeMarkdownGrid(w)
+		return nil
+	}
+	fmt.Fprintf(w, "| %s | Time |\n", column)
+	fmt.Fprintln(w, "|---|---:|")
+
+	if s.groupByDay {
+		s.writeMarkdownDays(w)
+		return nil
+	}
+
+	switch {
+	case s.groupByTag:
+		for _, t := range s.Tags {
+			writeMarkdownRow(w, t.Name, t.Duration)
+		}
+	case len(s.Projects) == 1:
+		for _, t := range s.Projects[0].Tasks {
+			writeMarkdownRow(w, t.Name, t.Duration)
+			for _, d := range t.Descriptions {
+				fmt.Fprintf(w, "| &nbsp;&nbsp;%s: %s | %s |\n", d.Date, markdownEscape(d.text()), d.display())
+			}
+		}
+	default:
+		for _, p := range s.Projects {
+			if p.Seconds > 0 {
+				writeMarkdownRow(w, p.Name, p.Duration)
+			}
+		}
+	}
+
+	if s.Pending != nil {
+		name := s.Pending.Project
+		if s.groupByTag {
+			name = s.Pending.tagNames()
+		} else if len(s.Projects) == 1 {
+			name = strings.TrimSpace(s.Pending.taskPrefix())
+		}
+		writeMarkdownRow(w, strings.TrimSpace(name+" (pending)"), s.Pending.Duration)
+	}
+
+	fmt.Fprintf(w, "| **Total** | **%s** |\n", s.Total.display())
+	s.writeMarkdownErrors(w)
+
+	return nil
+}
+
+func (s *Summary) writeMarkdownErrors(w io.Writer) {
+	if len(s.Errors) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n**Errors found %d:**\n\n", len(s.Errors))
+	for _, e := range s.Errors {
+		fmt.Fprintf(w, "- %s\n", markdownEscape(e))
+	}
+}
+
+// Writes a timesheet grid table for each week.
+func (s *Summary) writeMarkdownGrid(w io.Writer) {
+	for _, week := range s.Weeks {
+		start, _ := time.Parse("2006-01-02", week.Start)
+		fmt.Fprintf(w, "### Week of %s\n\n", start.Format("Jan 2, 2006"))
+
+		fmt.Fprintf(w, "| %s |", s.gridRowLabel())
+		for _, day := range week.Days {
+			d, _ := time.Parse("2006-01-02", day)
+			fmt.Fprintf(w, " %s |", d.Format("Mon 02"))
+		}
+		fmt.Fprintln(w, " Total |")
+		fmt.Fprintln(w, "|---|"+strings.Repeat("---:|", 8))
+
+		for _, row := range week.Rows {
+			fmt.Fprintf(w, "| %s |", markdownEscape(row.Name))
+			for _, d := range row.Days {
+				fmt.Fprintf(w, " %s |", formatCell(d.Seconds))
+			}
+			fmt.Fprintf(w, " %s |\n", formatCell(row.Seconds))
+		}
+
+		fmt.Fprint(w, "| **Total** |")
+		for _, d := range week.DayTotals {
+			fmt.Fprintf(w, " **%s** |", formatCell(d.Seconds))
+		}
+		fmt.Fprintf(w, " **%s** |\n\n", formatCell(week.Seconds))
+	}
+
+	if len(s.Weeks) > 1 {
+		fmt.Fprintf(w, "**Total:** %s\n", s.Total.display())
+	}
+	s.writeMarkdownErrors(w)
+}
+
+// Writes the timesheet rows, with a subtotal row for each day.
+func (s *Summary) writeMarkdownDays(w io.Writer) {
+	for _, d := range s.Days {
+		fmt.Fprintf(w, "| **%s** | |\n", formatDate(d.Date))
+		for _, t := range d.Tasks {
+			name := t.Name()
+			if t.Pending {
+				name += " (pending)"
+			}
+			writeMarkdownRow(w, name, t.Duration)
+		}
+		fmt.Fprintf(w, "| *Subtotal* | *%s* |\n", d.display())
+	}
+
+	fmt.Fprintf(w, "| **Total** | **%s** |\n", s.Total.display())
+	s.writeMarkdownErrors(w)
+}
+
+func writeMarkdownRow(w io.Writer, name string, d Duration) {
+	fmt.Fprintf(w, "| %s | %s |\n", markdownEscape(name), d.display())
+}
+
+// Escapes the characters which would break a table row or add formatting.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`).Replace(s)
+}
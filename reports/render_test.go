@@ -0,0 +1,247 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mrcook/time_warrior/reports/period"
+	"github.com/mrcook/time_warrior/timeslip"
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+// Returns a report for a week with two projects, and a pending timeslip.
+func newTestReport(t *testing.T) *Report {
+	p, err := period.Parse("2026-W42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New(p)
+
+	monday := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.Local)
+	slips := map[string][]*timeslip.Slip{
+		"Alpha": {
+			mustCompleted(t, "Alpha.api", monday, monday.Add(2*time.Hour+15*time.Minute)),
+			mustCompleted(t, "Alpha.docs", monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 1).Add(40*time.Minute)),
+		},
+		"Beta": {
+			mustCompleted(t, "Beta.x", monday.AddDate(0, 0, 2), monday.AddDate(0, 0, 2).Add(time.Hour)),
+		},
+	}
+	for _, name := range []string{"Beta", "Alpha"} {
+		project := slips[name]
+		r.ProcessProject(func(fn func(slip []byte) error) error {
+			for _, s := range project {
+				if err := fn(s.ToJson()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	return r
+}
+
+func mustCompleted(t *testing.T, name string, from, to time.Time) *timeslip.Slip {
+	s, err := timeslip.NewCompleted(name, from, to, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSummary_RenderText(t *testing.T) {
+	r := newTestReport(t)
+
+	var out bytes.Buffer
+	if err := r.Summary().Render(&out, FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Time Period: Week 42, 2026 (Oct 12, 2026 to Oct 18, 2026)
+
+   2h  55m : Alpha
+   1h   0m : Beta
+===========
+   3h  55m
+`
+	if out.String() != expected {
+		t.Errorf("unexpected text report:\n%s", out.String())
+	}
+}
+
+func TestSummary_RenderJSON(t *testing.T) {
+	r := newTestReport(t)
+
+	var out bytes.Buffer
+	if err := r.Summary().Render(&out, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	s := Summary{}
+	if err := json.Unmarshal(out.Bytes(), &s); err != nil {
+		t.Fatalf("invalid JSON: %s", err)
+	}
+
+	if s.Period == nil || s.Period.Name != "Week 42, 2026" {
+		t.Errorf("expected the report period, got %+v", s.Period)
+	}
+	if len(s.Projects) != 2 || s.Projects[0].Name != "Alpha" || s.Projects[0].Seconds != 10500 {
+		t.Errorf("unexpected projects: %+v", s.Projects)
+	}
+	if len(s.Projects[0].Tasks) != 2 || s.Projects[0].Tasks[0].Duration.Duration != "PT2H15M" {
+		t.Errorf("unexpected tasks: %+v", s.Projects[0].Tasks)
+	}
+	if s.Total.Seconds != 14100 {
+		t.Errorf("expected total of 14100 seconds, got %d", s.Total.Seconds)
+	}
+}
+
+func TestSummary_RenderCSV(t *testing.T) {
+	r := newTestReport(t)
+
+	var out bytes.Buffer
+	if err := r.Summary().Render(&out, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows, got:\n%s", out.String())
+	}
+	if lines[0] != "project,task,tag,seconds,duration,period_from,period_to,pending" {
+		t.Errorf("unexpected header: %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "Alpha,api,,8100,PT2H15M,2026-10-12T00:00:00") {
+		t.Errorf("unexpected row: %s", lines[1])
+	}
+}
+
+func TestSummary_DurationIgnoresDisplayFormat(t *testing.T) {
+	defer func(f worked.Format) { worked.DisplayFormat = f }(worked.DisplayFormat)
+	worked.DisplayFormat = worked.Clock
+
+	r := newTestReport(t)
+
+	var out bytes.Buffer
+	if err := r.Summary().Render(&out, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Alpha,api,,8100,PT2H15M,") {
+		t.Errorf("expected an ISO 8601 duration, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := r.Summary().Render(&out, FormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "| **Total** | **03:55** |") {
+		t.Errorf("expected markdown to use the display format, got:\n%s", out.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, expected := range map[string]Format{"text": FormatText, "JSON": FormatJSON, "csv": FormatCSV, "md": FormatMarkdown} {
+		f, err := ParseFormat(name)
+		if err != nil {
+			t.Errorf("unexpected error for '%s': %s", name, err)
+		}
+		if f != expected {
+			t.Errorf("expected %s, got %s", expected, f)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestSummary_Days(t *testing.T) {
+	r := newTestReport(t)
+	r.GroupByDay = true
+
+	// a timeslip worked over midnight is split across both days
+	late := time.Date(2026, time.October, 15, 23, 0, 0, 0, time.Local)
+	overnight := mustCompleted(t, "Gamma.deploy", late, late.Add(2*time.Hour))
+	r.ProcessProject(func(fn func(slip []byte) error) error {
+		return fn(overnight.ToJson())
+	})
+
+	s := r.Summary()
+
+	expected := []struct {
+		date    string
+		seconds int
+		tasks   []string
+	}{
+		{"2026-10-12", 8100, []string{"Alpha.api"}},
+		{"2026-10-13", 2400, []string{"Alpha.docs"}},
+		{"2026-10-14", 3600, []string{"Beta.x"}},
+		{"2026-10-15", 3600, []string{"Gamma.deploy"}},
+		{"2026-10-16", 3600, []string{"Gamma.deploy"}},
+	}
+
+	if len(s.Days) != len(expected) {
+		t.Fatalf("expected %d days, got %+v", len(expected), s.Days)
+	}
+	for i, e := range expected {
+		d := s.Days[i]
+		if d.Date != e.date || d.Seconds != e.seconds {
+			t.Errorf("expected %s with %d seconds, got %s with %d", e.date, e.seconds, d.Date, d.Seconds)
+		}
+		for j, name := range e.tasks {
+			if d.Tasks[j].Name() != name {
+				t.Errorf("expected task %s on %s, got %s", name, e.date, d.Tasks[j].Name())
+			}
+		}
+	}
+
+	if s.Total.Seconds != 21300 {
+		t.Errorf("expected total of 21300 seconds, got %d", s.Total.Seconds)
+	}
+}
+
+func TestSummary_RenderGrid(t *testing.T) {
+	r := newTestReport(t)
+	r.Grid = true
+
+	var out bytes.Buffer
+	if err := r.Summary().Render(&out, FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Time Period: Week 42, 2026 (Oct 12, 2026 to Oct 18, 2026)
+
+Week of Oct 12, 2026
+Project   Mon 12   Tue 13   Wed 14   Thu 15   Fri 16   Sat 17   Sun 18    Total
+Alpha      2h15m      40m        -        -        -        -        -    2h55m
+Beta           -        -    1h00m        -        -        -        -    1h00m
+-------------------------------------------------------------------------------
+Total      2h15m      40m    1h00m        -        -        -        -    3h55m
+`
+	if out.String() != expected {
+		t.Errorf("unexpected grid:\n%s", out.String())
+	}
+}
+
+func TestSummary_GridWeekStart(t *testing.T) {
+	period.WeekStart = time.Sunday
+	defer func() { period.WeekStart = time.Monday }()
+
+	r := newTestReport(t)
+	r.Grid = true
+
+	s := r.Summary()
+	if len(s.Weeks) != 1 {
+		t.Fatalf("expected one week, got %d", len(s.Weeks))
+	}
+	if s.Weeks[0].Start != "2026-10-11" {
+		t.Errorf("expected the week to start on Sunday 2026-10-11, got %s", s.Weeks[0].Start)
+	}
+	if s.Weeks[0].DayTotals[1].Seconds != 8100 {
+		t.Errorf("expected Monday in the second column, got %+v", s.Weeks[0].DayTotals)
+	}
+}
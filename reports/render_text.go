@@ -0,0 +1,248 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+// Writes the report as text for the terminal. A single project shows the
+// time worked on each of its tasks, otherwise the time worked on each project
+// or tag is shown.
+func (s *Summary) writeText(w io.Writer) error {
+	if s.grid && len(s.Weeks) > 0 {
+		s.writeTextGrid(w)
+	} else if s.groupByDay && len(s.Days) > 0 {
+		s.writeTextDays(w)
+	} else if s.groupByTag && len(s.Projects) > 0 {
+		s.writeTextTags(w)
+	} else if len(s.Projects) == 1 {
+		s.writeTextProjectTasks(w)
+	} else if len(s.Projects) > 1 {
+		s.writeTextProjects(w)
+	} else {
+		fmt.Fprintln(w, "No available data.")
+	}
+
+	if len(s.Errors) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Errors found %d:\n", len(s.Errors))
+		for _, e := range s.Errors {
+			fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+
+	return nil
+}
+
+// Displays all projects with their time worked.
+func (s *Summary) writeTextProjects(w io.Writer) {
+	s.writeTextHeader(w)
+
+	for _, p := range s.Projects {
+		if p.Seconds == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s : %s\n", formatColumn(p.Seconds, true), p.Name)
+	}
+
+	if s.Pending != nil {
+		fmt.Fprintln(w, "-----------")
+		fmt.Fprintf(w, "%s : %s pending timeslip\n", formatColumn(s.Pending.Seconds, false), s.Pending.Project)
+	}
+
+	writeTextTotal(w, s.Total)
+}
+
+// Displays the time worked for each tag, across all projects.
+func (s *Summary) writeTextTags(w io.Writer) {
+	s.writeTextHeader(w)
+
+	for _, t := range s.Tags {
+		fmt.Fprintf(w, "%s : %s\n", formatColumn(t.Seconds, true), t.Name)
+	}
+
+	if s.Pending != nil {
+		fmt.Fprintln(w, "-----------")
+		fmt.Fprintf(w, "%s : %s pending timeslip\n", formatColumn(s.Pending.Seconds, false), s.Pending.tagNames())
+	}
+
+	writeTextTotal(w, s.Total)
+}
+
+// Displays a timesheet of each day, with the time worked on its tasks, and a
+// subtotal for the day.
+func (s *Summary) writeTextDays(w io.Writer) {
+	s.writeTextHeader(w)
+
+	for i, d := range s.Days {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, formatDate(d.Date))
+
+		for _, t := range d.Tasks {
+			name := t.Name()
+			if t.Pending {
+				name += " (pending)"
+			}
+			fmt.Fprintf(w, "%s : %s\n", formatColumn(t.Seconds, true), name)
+		}
+
+		fmt.Fprintln(w, "-----------")
+		fmt.Fprintln(w, formatColumn(d.Seconds, false))
+	}
+
+	fmt.Fprintln(w)
+	writeTextTotal(w, s.Total)
+}
+
+// Displays a timesheet grid for each week, with the time worked on each
+// project, or task, per day, and the row and column totals.
+func (s *Summary) writeTextGrid(w io.Writer) {
+	s.writeTextHeader(w)
+
+	for i, week := range s.Weeks {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		start, _ := time.Parse("2006-01-02", week.Start)
+		fmt.Fprintf(w, "Week of %s\n", start.Format("Jan 2, 2006"))
+
+		nameWidth := len("Total")
+		if len(s.gridRowLabel()) > nameWidth {
+			nameWidth = len(s.gridRowLabel())
+		}
+		for _, row := range week.Rows {
+			if len(row.Name) > nameWidth {
+				nameWidth = len(row.Name)
+			}
+		}
+
+		fmt.Fprintf(w, "%-*s", nameWidth, s.gridRowLabel())
+		for _, day := range week.Days {
+			d, _ := time.Parse("2006-01-02", day)
+			fmt.Fprintf(w, " %8s", d.Format("Mon 02"))
+		}
+		fmt.Fprintf(w, " %8s\n", "Total")
+
+		for _, row := range week.Rows {
+			fmt.Fprintf(w, "%-*s", nameWidth, row.Name)
+			for _, d := range row.Days {
+				fmt.Fprintf(w, " %8s", formatCell(d.Seconds))
+			}
+			fmt.Fprintf(w, " %8s\n", formatCell(row.Seconds))
+		}
+
+		fmt.Fprintln(w, strings.Repeat("-", nameWidth+9*8))
+		fmt.Fprintf(w, "%-*s", nameWidth, "Total")
+		for _, d := range week.DayTotals {
+			fmt.Fprintf(w, " %8s", formatCell(d.Seconds))
+		}
+		fmt.Fprintf(w, " %8s\n", formatCell(week.Seconds))
+	}
+
+	if len(s.Weeks) > 1 {
+		fmt.Fprintln(w)
+		writeTextTotal(w, s.Total)
+	}
+}
+
+// Returns the heading for the grid rows, which are the tasks for a single
+// project report, otherwise the projects.
+func (s *Summary) gridRowLabel() string {
+	if len(s.Projects) == 1 {
+		return "Task"
+	}
+	return "Project"
+}
+
+// Displays project overview, along with all tasks and their time worked.
+func (s *Summary) writeTextProjectTasks(w io.Writer) {
+	p := s.Projects[0]
+
+	fmt.Fprintf(w, "Project Name: %s\n", p.Name)
+	if s.Period != nil {
+		fmt.Fprintf(w, "Time Period:  %s (%s)\n", s.Period.Name, s.Period.formattedDates())
+	}
+	if s.Rounding != "" {
+		fmt.Fprintf(w, "Rounding:     %s\n", s.Rounding)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Task List")
+
+	for _, t := range p.Tasks {
+		fmt.Fprintf(w, "%s : %s\n", formatColumn(t.Seconds, true), t.Name)
+
+		for _, d := range t.Descriptions {
+			fmt.Fprintf(w, "             %s %s  %s\n", d.Date, formatColumn(d.Seconds, true), d.text())
+		}
+	}
+
+	if s.Pending != nil {
+		fmt.Fprintln(w, "-----------")
+		fmt.Fprintf(w, "%s : %spending timeslip\n", formatColumn(s.Pending.Seconds, false), s.Pending.taskPrefix())
+	}
+
+	writeTextTotal(w, s.Total)
+}
+
+// Displays the time period and rounding policy, when set.
+func (s *Summary) writeTextHeader(w io.Writer) {
+	if s.Period != nil {
+		fmt.Fprintf(w, "Time Period: %s (%s)\n", s.Period.Name, s.Period.formattedDates())
+	}
+	if s.Rounding != "" {
+		fmt.Fprintf(w, "Rounding:    %s\n", s.Rounding)
+	}
+	if s.Period != nil || s.Rounding != "" {
+		fmt.Fprintln(w)
+	}
+}
+
+// Displays the total time worked for a report.
+func writeTextTotal(w io.Writer, total Duration) {
+	fmt.Fprintln(w, "===========")
+	fmt.Fprintln(w, formatColumn(total.Seconds, false))
+}
+
+// Returns the time worked, formatted for a timesheet grid cell, with a dash
+// when no time was worked.
+func formatCell(seconds int) string {
+	if seconds == 0 {
+		return "-"
+	}
+
+	wt := worked.WorkTime{}
+	wt.FromSeconds(seconds)
+
+	if worked.DisplayFormat != worked.Default {
+		return wt.Format(worked.DisplayFormat)
+	}
+	if wt.Hours == 0 {
+		return fmt.Sprintf("%dm", wt.Minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", wt.Hours, wt.Minutes)
+}
+
+// Returns the time worked, formatted for the time column of a report. In the
+// default format, a compact column shows only the minutes when under an hour.
+func formatColumn(seconds int, compact bool) string {
+	wt := worked.WorkTime{}
+	wt.FromSeconds(seconds)
+
+	if worked.DisplayFormat != worked.Default {
+		return fmt.Sprintf("%10s", wt.Format(worked.DisplayFormat))
+	}
+
+	if compact && wt.Hours == 0 {
+		return fmt.Sprintf("     %4dm", wt.Minutes)
+	}
+	return fmt.Sprintf("%4dh %3dm", wt.Hours, wt.Minutes)
+}
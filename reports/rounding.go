@@ -0,0 +1,92 @@
+package reports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+// RoundingScope is what the rounding of worked time is applied to.
+type RoundingScope string
+
+const (
+	RoundSlip  RoundingScope = "slip"  // each timeslip
+	RoundDay   RoundingScope = "day"   // the time worked on each task per day
+	RoundTotal RoundingScope = "total" // only the report total
+)
+
+// ParseRoundingScope returns the RoundingScope for the name.
+func ParseRoundingScope(name string) (RoundingScope, error) {
+	switch s := RoundingScope(strings.ToLower(name)); s {
+	case RoundSlip, RoundDay, RoundTotal:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown rounding scope, got '%s'. Expected: slip, day, or total", name)
+	}
+}
+
+// Rounding is a billing policy for rounding the time worked in a report.
+type Rounding struct {
+	Mode      worked.RoundingMode
+	Increment int // in seconds, with zero for no rounding
+	Scope     RoundingScope
+}
+
+// IsSet returns true if worked time should be rounded.
+func (r Rounding) IsSet() bool {
+	return r.Increment > 0
+}
+
+// String returns a description of the rounding policy, e.g. `up to 15m per slip`.
+func (r Rounding) String() string {
+	increment := worked.WorkTime{}
+	increment.FromSeconds(r.Increment)
+
+	scope := map[RoundingScope]string{
+		RoundSlip:  "per timeslip",
+		RoundDay:   "per task per day",
+		RoundTotal: "on the total",
+	}[r.Scope]
+
+	return fmt.Sprintf("%s to %s %s", r.Mode, increment.Format(worked.Default), scope)
+}
+
+// Round the seconds using the policy.
+func (r Rounding) round(seconds int) int {
+	return worked.RoundSeconds(seconds, r.Increment, r.Mode)
+}
+
+// Returns the total time worked for each group of the tasks, where a task may
+// belong to several groups. Rounding is applied to each task, or to the time
+// worked for each group per day, as per the policy scope. For rounding per day
+// the tasks must already be split by day, as a task is counted on the day it
+// was finished.
+func (r Rounding) totals(tasks []*task, groups func(t *task) []string) map[string]int {
+	totals := make(map[string]int)
+	days := make(map[string]map[string]int)
+
+	for _, t := range tasks {
+		for _, group := range groups(t) {
+			switch {
+			case r.IsSet() && r.Scope == RoundSlip:
+				totals[group] += r.round(t.timeWorked)
+			case r.IsSet() && r.Scope == RoundDay:
+				if days[group] == nil {
+					days[group] = make(map[string]int)
+				}
+				days[group][t.day()] += t.timeWorked
+			default:
+				totals[group] += t.timeWorked
+			}
+		}
+	}
+
+	for group, perDay := range days {
+		for _, seconds := range perDay {
+			totals[group] += r.round(seconds)
+		}
+	}
+
+	return totals
+}
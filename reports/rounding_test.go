@@ -0,0 +1,55 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+func TestRounding_Totals(t *testing.T) {
+	monday := int(time.Date(2026, time.October, 12, 12, 0, 0, 0, time.Local).Unix())
+	tuesday := monday + 24*60*60
+
+	tasks := []*task{
+		{name: "Api", finished: monday, timeWorked: 5 * 60},
+		{name: "Api", finished: monday, timeWorked: 5 * 60},
+		{name: "Api", finished: tuesday, timeWorked: 20 * 60},
+		{name: "Docs", finished: tuesday, timeWorked: 1 * 60},
+	}
+	byName := func(t *task) []string { return []string{t.name} }
+
+	tests := map[string]struct {
+		rounding  Rounding
+		api, docs int
+	}{
+		"no rounding": {Rounding{}, 30 * 60, 60},
+		"up per slip": {
+			Rounding{Mode: worked.RoundUp, Increment: 15 * 60, Scope: RoundSlip}, 60 * 60, 15 * 60,
+		},
+		"up per day": {
+			Rounding{Mode: worked.RoundUp, Increment: 15 * 60, Scope: RoundDay}, 45 * 60, 15 * 60,
+		},
+		"on the total": {
+			Rounding{Mode: worked.RoundUp, Increment: 15 * 60, Scope: RoundTotal}, 30 * 60, 60,
+		},
+	}
+
+	for msg, test := range tests {
+		t.Run(msg, func(t *testing.T) {
+			totals := test.rounding.totals(tasks, byName)
+
+			if totals["Api"] != test.api || totals["Docs"] != test.docs {
+				t.Errorf("expected %d/%d seconds, got %d/%d", test.api, test.docs, totals["Api"], totals["Docs"])
+			}
+		})
+	}
+}
+
+func TestRounding_String(t *testing.T) {
+	r := Rounding{Mode: worked.RoundUp, Increment: 6 * 60, Scope: RoundDay}
+
+	if r.String() != "up to 6 minutes per task per day" {
+		t.Errorf("unexpected description, got '%s'", r.String())
+	}
+}
@@ -0,0 +1,287 @@
+package reports
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+// Summary is the calculated result of a report, ready to be rendered in any
+// of the output formats.
+type Summary struct {
+	Period   *PeriodSummary   `json:"period,omitempty"`
+	Rounding string           `json:"rounding,omitempty"`
+	Projects []ProjectSummary `json:"projects"`
+	Tags     []TagSummary     `json:"tags,omitempty"`
+	Days     []DaySummary     `json:"days,omitempty"`
+	Weeks    []WeekSummary    `json:"weeks,omitempty"`
+	Pending  *PendingSummary  `json:"pending,omitempty"`
+	Total    Duration         `json:"total"`
+	Errors   []string         `json:"errors,omitempty"`
+
+	groupByTag bool
+	groupByDay bool
+	grid       bool
+}
+
+// PeriodSummary is the time period of a report.
+type PeriodSummary struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// ProjectSummary is the time worked on a project, and each of its tasks.
+type ProjectSummary struct {
+	Name string `json:"name"`
+	Duration
+	Tasks []TaskSummary `json:"tasks"`
+}
+
+// TaskSummary is the time worked on a task.
+type TaskSummary struct {
+	Name string `json:"name"`
+	Duration
+	Descriptions []DescriptionSummary `json:"descriptions,omitempty"`
+}
+
+// DescriptionSummary is the description of the work done on a task, with the
+// day it was finished and the time worked. Repeated descriptions may be
+// combined, with the count and the last day it was worked on.
+type DescriptionSummary struct {
+	Date  string `json:"date"`
+	Text  string `json:"text"`
+	Count int    `json:"count,omitempty"`
+	Duration
+}
+
+// TagSummary is the time worked on all timeslips with a tag.
+type TagSummary struct {
+	Name string `json:"name"`
+	Duration
+}
+
+// DaySummary is the time worked on each task during a day.
+type DaySummary struct {
+	Date string `json:"date"`
+	Duration
+	Tasks []DayTaskSummary `json:"tasks"`
+}
+
+// DayTaskSummary is the time worked on a task during a day.
+type DayTaskSummary struct {
+	Project string `json:"project"`
+	Task    string `json:"task"`
+	Pending bool   `json:"pending,omitempty"`
+	Duration
+}
+
+// Name returns the full task name, e.g. `Project.task`.
+func (t DayTaskSummary) Name() string {
+	if t.Task == "" {
+		return t.Project
+	}
+	return t.Project + "." + t.Task
+}
+
+// PendingSummary is the time worked on the pending timeslip.
+type PendingSummary struct {
+	Project string   `json:"project"`
+	Task    string   `json:"task,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Duration
+}
+
+// Duration is the time worked in seconds, along with its ISO 8601 duration,
+// which does not change with the display format.
+type Duration struct {
+	Seconds  int    `json:"seconds"`
+	Duration string `json:"duration"`
+}
+
+func newDuration(seconds int) Duration {
+	w := worked.WorkTime{}
+	w.FromSeconds(seconds)
+
+	return Duration{Seconds: seconds, Duration: w.Format(worked.ISO8601)}
+}
+
+// Returns the time worked in the display format.
+func (d Duration) display() string {
+	w := worked.WorkTime{}
+	w.FromSeconds(d.Seconds)
+
+	return w.String()
+}
+
+// Summary returns the calculated time worked for all processed projects,
+// their tasks and tags, and the pending timeslip, along with the total.
+func (r *Report) Summary() *Summary {
+	s := &Summary{groupByTag: r.GroupByTag, groupByDay: r.GroupByDay, grid: r.Grid}
+
+	if r.timePeriod.IsSet() {
+		s.Period = &PeriodSummary{Name: r.timePeriod.Period(), From: r.timePeriod.From(), To: r.timePeriod.To()}
+	}
+	if r.Rounding.IsSet() {
+		s.Rounding = r.Rounding.String()
+	}
+
+	r.sortProjectsByName()
+
+	total := 0
+	for _, p := range r.projects {
+		project := ProjectSummary{Name: p.name, Duration: newDuration(p.totalTimeWorked)}
+		for _, t := range p.sortedTasks() {
+			task := TaskSummary{Name: t.name, Duration: newDuration(t.timeWorked)}
+			if r.Descriptions {
+				task.Descriptions = p.descriptions(t.name, r.Rounding, r.DedupeDescriptions)
+			}
+			project.Tasks = append(project.Tasks, task)
+		}
+		s.Projects = append(s.Projects, project)
+		total += p.totalTimeWorked
+	}
+
+	if r.GroupByTag {
+		s.Tags = r.tagSummaries()
+	}
+
+	if pending := r.pendingTimeWorked(); pending > 0 && r.includesPending() {
+		s.Pending = &PendingSummary{
+			Project:  r.PendingTimeslip.Project,
+			Task:     r.PendingTimeslip.Task,
+			Tags:     r.PendingTimeslip.Tags,
+			Duration: newDuration(pending),
+		}
+		total += pending
+	}
+
+	if r.GroupByDay || r.Grid {
+		days := r.daySummaries()
+
+		// the timesheet total is the sum of the days, which may be rounded
+		total = 0
+		for _, d := range days {
+			total += d.Seconds
+		}
+
+		if r.GroupByDay {
+			s.Days = days
+		}
+		if r.Grid {
+			s.Weeks = weekSummaries(days, len(r.projects) == 1)
+		}
+	}
+
+	if r.Rounding.Scope == RoundTotal {
+		total = r.Rounding.round(total)
+	}
+	s.Total = newDuration(total)
+
+	s.Errors = r.errorMessages()
+
+	return s
+}
+
+// Returns the time worked for each tag, across all projects, sorted by name.
+// A timeslip with several tags has its time counted against each of them.
+func (r *Report) tagSummaries() []TagSummary {
+	tags := make(map[string]int)
+	for _, p := range r.projects {
+		for tag, timeWorked := range p.tags {
+			tags[tag] += timeWorked
+		}
+	}
+
+	var summaries []TagSummary
+	for name, timeWorked := range tags {
+		summaries = append(summaries, TagSummary{Name: name, Duration: newDuration(timeWorked)})
+	}
+	sortByName(summaries, func(t TagSummary) string { return t.Name })
+
+	return summaries
+}
+
+// Returns the time worked on each task per day, across all projects, sorted
+// by date. The pending timeslip is included in the days it was worked on.
+func (r *Report) daySummaries() []DaySummary {
+	days := make(map[string]*DaySummary)
+	add := func(date string, t DayTaskSummary) {
+		if days[date] == nil {
+			days[date] = &DaySummary{Date: date}
+		}
+		days[date].Tasks = append(days[date].Tasks, t)
+		days[date].Seconds += t.Seconds
+	}
+
+	for _, p := range r.projects {
+		for date, tasks := range p.dailyTotals(r.Rounding) {
+			for name, seconds := range tasks {
+				if name == "." {
+					name = ""
+				}
+				add(date, DayTaskSummary{Project: p.name, Task: name, Duration: newDuration(seconds)})
+			}
+		}
+	}
+
+	if r.pendingTimeWorked() > 0 && r.includesPending() {
+		pending := &task{
+			name:    r.PendingTimeslip.Task,
+			started: r.PendingTimeslip.Started,
+			slip:    &r.PendingTimeslip,
+		}
+		from, to := periodBounds(r.timePeriod)
+		for _, d := range pending.days(from, to) {
+			seconds := d.timeWorked
+			if r.Rounding.Scope != RoundTotal {
+				seconds = r.Rounding.round(seconds)
+			}
+			add(d.day(), DayTaskSummary{Project: r.PendingTimeslip.Project, Task: d.name, Pending: true, Duration: newDuration(seconds)})
+		}
+	}
+
+	var summaries []DaySummary
+	for _, d := range days {
+		sort.SliceStable(d.Tasks, func(i, j int) bool {
+			a, b := d.Tasks[i], d.Tasks[j]
+			if a.Pending != b.Pending {
+				return b.Pending
+			}
+			return strings.ToLower(a.Name()) < strings.ToLower(b.Name())
+		})
+		d.Duration = newDuration(d.Seconds)
+		summaries = append(summaries, *d)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Date < summaries[j].Date })
+
+	return summaries
+}
+
+// Returns the report errors, followed by the timeslips that could not be read
+// for each project.
+func (r *Report) errorMessages() []string {
+	var messages []string
+
+	for _, e := range r.errors {
+		messages = append(messages, e.Error())
+	}
+
+	for _, p := range r.projects {
+		for _, e := range p.scanErrors {
+			messages = append(messages, fmt.Sprintf("%s: %s: %s", p.name, e.scanner, e.timeslip))
+		}
+	}
+
+	return messages
+}
+
+// Sorts the items by their name, ignoring case.
+func sortByName[T any](items []T, name func(T) string) {
+	sort.Slice(items, func(i, j int) bool {
+		return strings.ToLower(name(items[i])) < strings.ToLower(name(items[j]))
+	})
+}
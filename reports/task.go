@@ -1,6 +1,10 @@
 package reports
 
-import "github.com/mrcook/time_warrior/timeslip"
+import (
+	"time"
+
+	"github.com/mrcook/time_warrior/timeslip"
+)
 
 type task struct {
 	name       string
@@ -8,6 +12,8 @@ type task struct {
 	started    int
 	finished   int
 	timeWorked int
+	tags       []string
+	slip       *timeslip.Slip
 }
 
 // Creates a new task from a timeslip JSON string.
@@ -30,7 +36,65 @@ func newTask(jsonData []byte) (*task, error) {
 		started:    slip.Started,
 		finished:   slip.Finished,
 		timeWorked: slip.Worked,
+		tags:       slip.Tags,
+		slip:       slip,
 	}
 
 	return t, nil
 }
+
+// Returns the day the task was finished, e.g. `2019-01-13`.
+func (t task) day() string {
+	return time.Unix(int64(t.finished), 0).Format("2006-01-02")
+}
+
+// Splits the time worked on the task into a task for each day it was worked
+// on, within the from/to time range. An unset time is not bounded.
+func (t *task) days(from, to time.Time) []*task {
+	start := time.Unix(int64(t.started), 0)
+	end := time.Now()
+	if t.finished > 0 {
+		end = time.Unix(int64(t.finished), 0)
+	}
+	if !from.IsZero() && start.Before(from) {
+		start = from
+	}
+	if !to.IsZero() && end.After(to) {
+		end = to
+	}
+
+	var days []*task
+	for day := beginningOfDay(start); !day.After(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+
+		dayStart, dayEnd := day, next
+		if dayStart.Before(start) {
+			dayStart = start
+		}
+		if !to.IsZero() && dayEnd.After(to) {
+			dayEnd = to
+		}
+
+		worked := t.slip.TimeWorkedBetween(dayStart, dayEnd)
+		if worked == 0 {
+			continue
+		}
+
+		days = append(days, &task{
+			name:       t.name,
+			project:    t.project,
+			started:    int(dayStart.Unix()),
+			finished:   int(dayStart.Unix()),
+			timeWorked: worked,
+			tags:       t.tags,
+			slip:       t.slip,
+		})
+	}
+
+	return days
+}
+
+func beginningOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,61 @@
+package timeslip
+
+import "sort"
+
+// Segment represents a single period of work on a timeslip, from a
+// start/resume until the following pause/done.
+// Note: timestamps are stored as Unix time.
+type Segment struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Duration returns the number of seconds worked during the segment.
+func (s Segment) Duration() int {
+	return s.End - s.Start
+}
+
+// Overlap returns the number of seconds of the segment within the time
+// range, from the start up to, but not including, the end.
+func (s Segment) Overlap(start, end int) int {
+	if s.Start > start {
+		start = s.Start
+	}
+	if s.End < end {
+		end = s.End
+	}
+	if end <= start {
+		return 0
+	}
+	return end - start
+}
+
+// Returns the total number of seconds worked across all segments.
+func sumSegments(segments []Segment) int {
+	total := 0
+	for _, s := range segments {
+		total += s.Duration()
+	}
+	return total
+}
+
+// Sorts the segments by their start time, joining any that overlap or touch.
+func mergeSegments(segments []Segment) []Segment {
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].Start < segments[j].Start
+	})
+
+	var merged []Segment
+	for _, s := range segments {
+		last := len(merged) - 1
+		if last >= 0 && s.Start <= merged[last].End {
+			if s.End > merged[last].End {
+				merged[last].End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	return merged
+}
@@ -4,6 +4,7 @@ package timeslip
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -15,26 +16,47 @@ import (
 
 // Slip represents a timeslip.
 // Note: timestamps are stored as Unix time.
+//
+// Segments record each completed period of work, in order. The period for a
+// started/resumed timeslip is still open, running from Modified until now.
+// Timeslips saved by older versions have no segments, and only the Worked
+// time is available.
 type Slip struct {
-	Project     string `json:"project"`
-	Task        string `json:"task"`
-	Description string `json:"description"`
-	Started     int    `json:"started"`
-	Worked      int    `json:"worked"`
-	Finished    int    `json:"finished"`
-	Modified    int    `json:"modified"`
-	Status      string `json:"status"`
-	UUID        string `json:"uuid"`
-}
-
-// New returns a new "started" timeslip.
-func New(name string) (*Slip, error) {
-	currentTime := int(time.Now().Unix())
+	Project     string    `json:"project"`
+	Task        string    `json:"task"`
+	Description string    `json:"description"`
+	Started     int       `json:"started"`
+	Worked      int       `json:"worked"`
+	Finished    int       `json:"finished"`
+	Modified    int       `json:"modified"`
+	Status      string    `json:"status"`
+	UUID        string    `json:"uuid"`
+	Tags        []string  `json:"tags,omitempty"`
+	Segments    []Segment `json:"segments,omitempty"`
+}
+
+// New returns a new "started" timeslip, with any optional tags.
+func New(name string, tags ...string) (*Slip, error) {
+	return NewAt(time.Now(), name, tags...)
+}
+
+// NewAt returns a new timeslip, "started" at the given time.
+func NewAt(at time.Time, name string, tags ...string) (*Slip, error) {
+	if at.After(time.Now()) {
+		return nil, errFutureTime
+	}
+
+	currentTime := int(at.Unix())
 	project, task, err := parseProjectName(name)
 	if err != nil {
 		return nil, err
 	}
 
+	tags, err = ParseTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
 	slip := &Slip{
 		Project:     project,
 		Task:        task,
@@ -43,27 +65,136 @@ func New(name string) (*Slip, error) {
 		Modified:    currentTime,
 		Status:      status.Started,
 		UUID:        uuid.New().String(),
+		Tags:        tags,
+	}
+
+	return slip, nil
+}
+
+// NewCompleted returns a "completed" timeslip for work done between the
+// from/to times, for logging past work.
+func NewCompleted(name string, from, to time.Time, description string, tags ...string) (*Slip, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("finish time must be after the start time")
+	}
+
+	slip, err := NewAt(from, name, tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := slip.DoneAt(to, description); err != nil {
+		return nil, err
 	}
 
 	return slip, nil
 }
 
+var tagFormat = regexp.MustCompile(`^[[:alnum:]_-]+$`)
+
+// ParseTags returns the tag names in lower case, with any `+` prefix removed
+// and duplicates dropped. Tags may only contain alphanumeric, dash, and
+// underscore characters.
+func ParseTags(tags []string) ([]string, error) {
+	for _, tag := range tags {
+		name := strings.TrimPrefix(tag, "+")
+		if !tagFormat.MatchString(name) {
+			return nil, fmt.Errorf("bad tag format, got '%s'", name)
+		}
+	}
+	return normaliseTags(tags), nil
+}
+
+// Returns the tags in lower case, without any `+` prefix or duplicates.
+func normaliseTags(tags []string) []string {
+	var normalised []string
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(tag, "+"))
+		if !ContainsTag(normalised, tag) {
+			normalised = append(normalised, tag)
+		}
+	}
+
+	return normalised
+}
+
+// ParseTagArgs returns the tag names given as command arguments, which must
+// each be prefixed with a `+`.
+func ParseTagArgs(args []string) ([]string, error) {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "+") {
+			return nil, fmt.Errorf("tags must be prefixed with a '+', got '%s'", arg)
+		}
+	}
+	return ParseTags(args)
+}
+
+// ParseTagDescriptionArgs returns the tag names and the description given as
+// command arguments, where the description is the last argument. A final
+// argument prefixed with a `+` is a tag, so the description is missing.
+func ParseTagDescriptionArgs(args []string) ([]string, string, error) {
+	if len(args) == 0 || strings.HasPrefix(args[len(args)-1], "+") {
+		return nil, "", fmt.Errorf("a description is required after any tags")
+	}
+
+	tags, err := ParseTagArgs(args[:len(args)-1])
+	if err != nil {
+		return nil, "", err
+	}
+	return tags, args[len(args)-1], nil
+}
+
+// HasTag returns true if the timeslip has been given the tag.
+func (s *Slip) HasTag(tag string) bool {
+	return ContainsTag(s.Tags, tag)
+}
+
+// ContainsTag returns true if the tag is in the list, ignoring case.
+func ContainsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
 // Unmarshal parses the JSON-encoded data and stores the result
-// in the struct pointed to by `slip`.
+// in the struct pointed to by `slip`, with the tag names in lower case.
 func Unmarshal(data []byte, slip *Slip) error {
-	return json.Unmarshal(data, slip)
+	if err := json.Unmarshal(data, slip); err != nil {
+		return err
+	}
+	slip.Tags = normaliseTags(slip.Tags)
+	return nil
 }
 
+// DateFormat is the time layout used when displaying timeslip timestamps.
+var DateFormat = "2006-01-02 15:04"
+
+var errFutureTime = fmt.Errorf("time can not be in the future")
+
 // Pause a started timeslip.
 func (s *Slip) Pause() error {
+	return s.PauseAt(time.Now())
+}
+
+// PauseAt pauses a started timeslip at the given time, which must not be
+// earlier than when the timeslip was started/resumed.
+func (s *Slip) PauseAt(at time.Time) error {
 	if s.Status == status.Paused {
 		return fmt.Errorf("slip is already paused")
 	}
 
-	now := int(time.Now().Unix())
+	if err := s.checkTransitionTime(at); err != nil {
+		return err
+	}
+
+	now := int(at.Unix())
 
+	s.closeSegment(now)
 	s.Status = status.Paused
-	s.Worked += now - s.Modified
 	s.Modified = now
 
 	return nil
@@ -71,22 +202,43 @@ func (s *Slip) Pause() error {
 
 // Resume a paused timeslip.
 func (s *Slip) Resume() error {
+	return s.ResumeAt(time.Now())
+}
+
+// ResumeAt resumes a paused timeslip at the given time, which must not be
+// earlier than when the timeslip was paused.
+func (s *Slip) ResumeAt(at time.Time) error {
 	if s.Status != status.Paused {
 		return fmt.Errorf("slip is already in progress")
 	}
 
+	if err := s.checkTransitionTime(at); err != nil {
+		return err
+	}
+
 	s.Status = status.Resumed
-	s.Modified = int(time.Now().Unix())
+	s.Modified = int(at.Unix())
 
 	return nil
 }
 
 // Done marks a timeslip as completed.
 func (s *Slip) Done(description string) {
-	currentTime := int(time.Now().Unix())
+	_ = s.DoneAt(time.Now(), description)
+}
+
+// DoneAt marks a timeslip as completed at the given time, which must not be
+// earlier than the last change to the timeslip. A paused timeslip is always
+// finished at the time it was paused.
+func (s *Slip) DoneAt(at time.Time, description string) error {
+	if err := s.checkTransitionTime(at); err != nil {
+		return err
+	}
+
+	currentTime := int(at.Unix())
 
 	if s.Status == status.Started || s.Status == status.Resumed {
-		s.Worked += currentTime - s.Modified
+		s.closeSegment(currentTime)
 		s.Finished = currentTime
 		s.Modified = currentTime
 	} else {
@@ -95,6 +247,89 @@ func (s *Slip) Done(description string) {
 
 	s.Description = description
 	s.Status = status.Completed
+
+	return nil
+}
+
+// Retime changes the started and finished times, and the time worked, on a
+// completed timeslip. Any segments are clipped to the new times, and then
+// grown or shrunk to match the time worked.
+func (s *Slip) Retime(started, finished, worked int) error {
+	if s.Status != status.Completed {
+		return fmt.Errorf("only a completed timeslip can be retimed")
+	}
+
+	retimed := *s
+	retimed.Started = started
+	retimed.Finished = finished
+	retimed.Worked = worked
+	retimed.Segments = nil
+
+	if err := retimed.Validate(); err != nil {
+		return err
+	}
+
+	if len(s.Segments) > 0 || s.Worked == 0 {
+		for _, segment := range s.Segments {
+			if segment.Overlap(started, finished) > 0 {
+				retimed.Segments = append(retimed.Segments, Segment{
+					Start: max(segment.Start, started),
+					End:   min(segment.End, finished),
+				})
+			}
+		}
+
+		// segments are filled in back from the finish time
+		modified := retimed.Modified
+		retimed.Modified = finished
+		retimed.fitSegments()
+		retimed.Modified = modified
+	}
+
+	*s = retimed
+
+	return nil
+}
+
+// Validate returns an error if a completed timeslip is not consistent, such
+// as being finished before it was started, or having more time worked than
+// between those times.
+func (s *Slip) Validate() error {
+	if s.Status != status.Completed {
+		return fmt.Errorf("timeslip status must be '%s', got '%s'", status.Completed, s.Status)
+	}
+	if _, _, err := parseProjectName(s.Name()); err != nil || s.Project == "" {
+		return fmt.Errorf("bad Project/Task name, got '%s'", s.Name())
+	}
+	if s.UUID == "" {
+		return fmt.Errorf("timeslip is missing a UUID")
+	}
+	if _, err := ParseTags(s.Tags); err != nil {
+		return err
+	}
+
+	if s.Finished < s.Started {
+		return fmt.Errorf("finish time must be after the start time")
+	}
+	if s.Worked < 0 {
+		return fmt.Errorf("worked time can not be negative")
+	}
+	if s.Worked > s.Finished-s.Started {
+		return fmt.Errorf("worked time can not be more than the time between the start and finish")
+	}
+
+	end := s.Started
+	for _, segment := range s.Segments {
+		if segment.Start < end || segment.End < segment.Start || segment.End > s.Finished {
+			return fmt.Errorf("segments must be in order, between the start and finish times")
+		}
+		end = segment.End
+	}
+	if len(s.Segments) > 0 && sumSegments(s.Segments) != s.Worked {
+		return fmt.Errorf("segments total %ds, expected the worked time of %ds", sumSegments(s.Segments), s.Worked)
+	}
+
+	return nil
 }
 
 // Adjust the current worked time from the given string value.
@@ -107,6 +342,7 @@ func (s *Slip) Adjust(adjustment string) error {
 	if s.Status != status.Paused {
 		s.Pause()
 	}
+	tracked := s.tracksSegments()
 
 	a := worked.WorkTime{}
 	if err := a.FromString(adjustment); err != nil {
@@ -121,6 +357,9 @@ func (s *Slip) Adjust(adjustment string) error {
 
 	if s.Worked < 0 {
 		s.Worked = 0
+		if tracked {
+			s.Segments = nil
+		}
 		return nil
 	}
 
@@ -133,6 +372,10 @@ func (s *Slip) Adjust(adjustment string) error {
 		s.Started = s.Modified - s.Worked
 	}
 
+	if tracked {
+		s.fitSegments()
+	}
+
 	// update the status if it wasn't paused
 	if lastStatus != status.Paused {
 		s.Status = lastStatus
@@ -153,25 +396,80 @@ func (s *Slip) Name() string {
 // If a timeslip is started/resumed, the worked time is adjusted based
 // on the modified and current time.
 func (s *Slip) TotalTimeWorked() int {
+	worked := s.Worked
+	if len(s.Segments) > 0 {
+		worked = sumSegments(s.Segments)
+	}
+
 	if s.Status == "" || s.Status == status.Paused || s.Status == status.Completed {
-		return s.Worked
+		return worked
 	}
-	return int(time.Now().Unix()) - s.Modified + s.Worked
+	return int(time.Now().Unix()) - s.Modified + worked
+}
+
+// Intervals returns all the periods worked on a timeslip, in order.
+// If a timeslip is started/resumed, the current period is included,
+// ending at the current time.
+func (s *Slip) Intervals() []Segment {
+	intervals := make([]Segment, len(s.Segments))
+	copy(intervals, s.Segments)
+
+	if s.Status == status.Started || s.Status == status.Resumed {
+		intervals = append(intervals, Segment{Start: s.Modified, End: int(time.Now().Unix())})
+	}
+
+	return intervals
+}
+
+// TimeWorkedBetween returns the seconds worked on the timeslip from the start
+// time up to, but not including, the end time. When the work intervals are
+// not known, the time worked is spread evenly between Started and Finished,
+// or the current time for a timeslip still in progress.
+func (s *Slip) TimeWorkedBetween(from, to time.Time) int {
+	start, end := int(from.Unix()), int(to.Unix())
+
+	if s.tracksSegments() {
+		worked := 0
+		for _, interval := range s.Intervals() {
+			worked += interval.Overlap(start, end)
+		}
+		return worked
+	}
+
+	finished := s.Finished
+	if finished == 0 {
+		finished = int(time.Now().Unix())
+	}
+	span := Segment{Start: s.Started, End: finished}
+
+	if span.Duration() <= 0 {
+		if finished >= start && finished < end {
+			return s.TotalTimeWorked()
+		}
+		return 0
+	}
+
+	return int(int64(s.TotalTimeWorked()) * int64(span.Overlap(start, end)) / int64(span.Duration()))
 }
 
 // String returns a CLI friendly representation of the timeslip.
 func (s *Slip) String() string {
-	started := time.Unix(int64(s.Started), 0).Format("2006-01-02 15:04")
+	started := time.Unix(int64(s.Started), 0).Format(DateFormat)
 
 	w := worked.WorkTime{}
 	w.FromSeconds(s.TotalTimeWorked())
 
 	timestampSuffix := ""
 	if s.Status == status.Paused || s.Status == status.Resumed {
-		timestampSuffix = fmt.Sprintf(" (%s)", time.Unix(int64(s.Modified), 0).Format("2006-01-02 15:04"))
+		timestampSuffix = fmt.Sprintf(" (%s)", time.Unix(int64(s.Modified), 0).Format(DateFormat))
 	}
 
-	return fmt.Sprintf("%s | Started: %s | Worked: %s | Status: %s%s", s.Name(), started, w.String(), s.Status, timestampSuffix)
+	name := s.Name()
+	for _, tag := range s.Tags {
+		name += " +" + tag
+	}
+
+	return fmt.Sprintf("%s | Started: %s | Worked: %s | Status: %s%s", name, started, w.String(), s.Status, timestampSuffix)
 }
 
 // ToJson converts a timeslip to a JSON string.
@@ -183,6 +481,80 @@ func (s *Slip) ToJson() []byte {
 	return data
 }
 
+// Checks that a state change at the given time keeps the timeslip in order.
+func (s *Slip) checkTransitionTime(at time.Time) error {
+	if at.After(time.Now()) {
+		return errFutureTime
+	}
+
+	if int(at.Unix()) < s.Modified {
+		lastChange := time.Unix(int64(s.Modified), 0).Format(DateFormat)
+		return fmt.Errorf("time can not be before the last change to the timeslip (%s)", lastChange)
+	}
+
+	return nil
+}
+
+// Closes the current work period at the given time, adding it to the worked
+// time, and recording it as a segment when the timeslip supports them.
+func (s *Slip) closeSegment(end int) {
+	if s.tracksSegments() && end > s.Modified {
+		s.Segments = append(s.Segments, Segment{Start: s.Modified, End: end})
+	}
+	s.Worked += end - s.Modified
+}
+
+// Timeslips from older versions have worked time without any segments, and
+// these can not be reconstructed, so segments are only recorded for timeslips
+// that have them already, or have no worked time yet.
+func (s *Slip) tracksSegments() bool {
+	return len(s.Segments) > 0 || s.Worked == 0
+}
+
+// Grow or shrink the segments so their total matches the worked time after
+// an adjustment. Excess time is removed from the most recent segments, while
+// missing time fills the gaps between Started and Modified, latest first.
+func (s *Slip) fitSegments() {
+	total := sumSegments(s.Segments)
+
+	for excess := total - s.Worked; excess > 0 && len(s.Segments) > 0; {
+		last := &s.Segments[len(s.Segments)-1]
+		if last.Duration() <= excess {
+			excess -= last.Duration()
+			s.Segments = s.Segments[:len(s.Segments)-1]
+		} else {
+			last.End -= excess
+			excess = 0
+		}
+	}
+
+	need := s.Worked - total
+	boundary := s.Modified
+	var filled []Segment
+
+	for i := len(s.Segments) - 1; i >= -1 && need > 0; i-- {
+		gapStart := s.Started
+		if i >= 0 {
+			gapStart = s.Segments[i].End
+		}
+
+		if boundary > gapStart {
+			start := gapStart
+			if boundary-start > need {
+				start = boundary - need
+			}
+			filled = append(filled, Segment{Start: start, End: boundary})
+			need -= boundary - start
+		}
+
+		if i >= 0 {
+			boundary = s.Segments[i].Start
+		}
+	}
+
+	s.Segments = mergeSegments(append(s.Segments, filled...))
+}
+
 func parseProjectName(name string) (string, string, error) {
 	names := strings.Split(name, ".")
 
@@ -605,12 +605,12 @@ func TestSlip_AdjustWorked(t *testing.T) {
 
 		worked := slip.Worked
 
-		err := slip.Adjust("1")
+		err := slip.Adjust("1h1")
 		if err == nil {
 			t.Fatalf("expected an error")
 		}
 
-		if err.Error() != "invalid time unit, got '1'" {
+		if err.Error() != "missing time unit after '1'" {
 			t.Errorf("expected invalid unit error, got '%s'", err)
 		}
 
@@ -681,3 +681,578 @@ func TestSlip_AdjustWorked(t *testing.T) {
 		}
 	})
 }
+
+func TestSlip_Segments(t *testing.T) {
+	unixNow := func() int { return int(time.Now().Unix()) }
+
+	t.Run("pausing records a segment", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{Started: now - 60, Modified: now - 60, Status: status.Started}
+
+		_ = ts.Pause()
+
+		if len(ts.Segments) != 1 {
+			t.Fatalf("expected 1 segment, got %d", len(ts.Segments))
+		}
+		if ts.Segments[0].Start != now-60 || ts.Segments[0].End != ts.Modified {
+			t.Errorf("unexpected segment, got %+v", ts.Segments[0])
+		}
+	})
+
+	t.Run("each pause/resume adds a new segment", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{
+			Started:  now - 100,
+			Worked:   20,
+			Modified: now - 30,
+			Status:   status.Resumed,
+			Segments: []timeslip.Segment{{Start: now - 100, End: now - 80}},
+		}
+
+		_ = ts.Pause()
+
+		if len(ts.Segments) != 2 {
+			t.Fatalf("expected 2 segments, got %d", len(ts.Segments))
+		}
+		if ts.Segments[1].Start != now-30 {
+			t.Errorf("expected segment to start when resumed, got %d", ts.Segments[1].Start)
+		}
+		if ts.Worked != 50 || ts.TotalTimeWorked() != 50 {
+			t.Errorf("expected 50 seconds worked, got %d (total %d)", ts.Worked, ts.TotalTimeWorked())
+		}
+	})
+
+	t.Run("done closes the current segment", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{
+			Started:  now - 100,
+			Worked:   20,
+			Modified: now - 10,
+			Status:   status.Resumed,
+			Segments: []timeslip.Segment{{Start: now - 100, End: now - 80}},
+		}
+
+		ts.Done("Completing with segments")
+
+		if len(ts.Segments) != 2 {
+			t.Fatalf("expected 2 segments, got %d", len(ts.Segments))
+		}
+		if ts.Segments[1].End != ts.Finished {
+			t.Errorf("expected last segment to end at finished time %d, got %d", ts.Finished, ts.Segments[1].End)
+		}
+		if ts.TotalTimeWorked() != 30 {
+			t.Errorf("expected 30 seconds worked, got %d", ts.TotalTimeWorked())
+		}
+	})
+
+	t.Run("timeslips without segments do not record them", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{Started: now - 100, Worked: 20, Modified: now - 10, Status: status.Resumed}
+
+		_ = ts.Pause()
+
+		if len(ts.Segments) != 0 {
+			t.Errorf("expected no segments, got %d", len(ts.Segments))
+		}
+		if ts.TotalTimeWorked() != 30 {
+			t.Errorf("expected 30 seconds worked, got %d", ts.TotalTimeWorked())
+		}
+	})
+
+	t.Run("intervals include the current period", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{
+			Started:  now - 100,
+			Worked:   20,
+			Modified: now - 10,
+			Status:   status.Resumed,
+			Segments: []timeslip.Segment{{Start: now - 100, End: now - 80}},
+		}
+
+		intervals := ts.Intervals()
+		if len(intervals) != 2 {
+			t.Fatalf("expected 2 intervals, got %d", len(intervals))
+		}
+		if intervals[1].Start != now-10 || intervals[1].End < now {
+			t.Errorf("unexpected current interval, got %+v", intervals[1])
+		}
+		if len(ts.Segments) != 1 {
+			t.Errorf("expected segments to be unchanged, got %d", len(ts.Segments))
+		}
+	})
+}
+
+func TestSlip_AdjustSegments(t *testing.T) {
+	unixNow := func() int { return int(time.Now().Unix()) }
+
+	t.Run("positive adjustment fills the latest gap", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{
+			Started:  now - 60,
+			Worked:   10,
+			Modified: now - 30,
+			Status:   status.Paused,
+			Segments: []timeslip.Segment{{Start: now - 60, End: now - 50}},
+		}
+
+		if err := ts.Adjust("10s"); err != nil {
+			t.Fatalf("unexpected error on adjust %s", err)
+		}
+
+		expected := []timeslip.Segment{{Start: now - 60, End: now - 50}, {Start: now - 40, End: now - 30}}
+		if fmt.Sprint(ts.Segments) != fmt.Sprint(expected) {
+			t.Errorf("expected segments %v, got %v", expected, ts.Segments)
+		}
+	})
+
+	t.Run("positive adjustment which moves the started time", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{
+			Started:  now - 60,
+			Worked:   50,
+			Modified: now - 10,
+			Status:   status.Paused,
+			Segments: []timeslip.Segment{{Start: now - 60, End: now - 10}},
+		}
+
+		if err := ts.Adjust("15s"); err != nil {
+			t.Fatalf("unexpected error on adjust %s", err)
+		}
+
+		expected := []timeslip.Segment{{Start: now - 75, End: now - 10}}
+		if fmt.Sprint(ts.Segments) != fmt.Sprint(expected) {
+			t.Errorf("expected segments %v, got %v", expected, ts.Segments)
+		}
+		if ts.TotalTimeWorked() != ts.Worked {
+			t.Errorf("expected total %d to equal worked %d", ts.TotalTimeWorked(), ts.Worked)
+		}
+	})
+
+	t.Run("negative adjustment trims the latest segments", func(t *testing.T) {
+		now := unixNow()
+		ts := timeslip.Slip{
+			Started:  now - 60,
+			Worked:   30,
+			Modified: now - 10,
+			Status:   status.Paused,
+			Segments: []timeslip.Segment{{Start: now - 60, End: now - 40}, {Start: now - 20, End: now - 10}},
+		}
+
+		if err := ts.Adjust("-15s"); err != nil {
+			t.Fatalf("unexpected error on adjust %s", err)
+		}
+
+		expected := []timeslip.Segment{{Start: now - 60, End: now - 45}}
+		if fmt.Sprint(ts.Segments) != fmt.Sprint(expected) {
+			t.Errorf("expected segments %v, got %v", expected, ts.Segments)
+		}
+		if ts.TotalTimeWorked() != 15 {
+			t.Errorf("expected 15 seconds worked, got %d", ts.TotalTimeWorked())
+		}
+	})
+}
+
+func TestSlip_NewFromJSONWithSegments(t *testing.T) {
+	var jsonBlob = []byte(`{"project":"timeWarrior","worked":30,"status":"completed","segments":[{"start":100,"end":110},{"start":200,"end":220}]}`)
+
+	slip := &timeslip.Slip{}
+	if err := timeslip.Unmarshal(jsonBlob, slip); err != nil {
+		t.Fatal("failed to unmarshal the JSON data: ", err)
+	}
+
+	if len(slip.Segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(slip.Segments))
+	}
+	if slip.Segments[1].Start != 200 || slip.Segments[1].End != 220 {
+		t.Errorf("unexpected segment, got %+v", slip.Segments[1])
+	}
+	if slip.TotalTimeWorked() != 30 {
+		t.Errorf("expected 30 seconds worked, got %d", slip.TotalTimeWorked())
+	}
+}
+
+func TestParseTagArgs(t *testing.T) {
+	t.Run("with prefixed tags", func(t *testing.T) {
+		tags, err := timeslip.ParseTagArgs([]string{"+meeting", "+billable"})
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if fmt.Sprint(tags) != "[meeting billable]" {
+			t.Errorf("expected tags without the prefix, got %v", tags)
+		}
+	})
+
+	t.Run("with a tag missing the prefix", func(t *testing.T) {
+		_, err := timeslip.ParseTagArgs([]string{"+meeting", "billable"})
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+
+		if err.Error() != "tags must be prefixed with a '+', got 'billable'" {
+			t.Errorf("unexpected error, got '%s'", err)
+		}
+	})
+}
+
+func TestParseTagDescriptionArgs(t *testing.T) {
+	t.Run("with tags and a description", func(t *testing.T) {
+		tags, description, err := timeslip.ParseTagDescriptionArgs([]string{"+Meeting", "+billable", "Planning"})
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if fmt.Sprint(tags) != "[meeting billable]" {
+			t.Errorf("expected the tags without the prefix, got %v", tags)
+		}
+		if description != "Planning" {
+			t.Errorf("expected the last argument as the description, got '%s'", description)
+		}
+	})
+
+	t.Run("with only a description", func(t *testing.T) {
+		tags, description, err := timeslip.ParseTagDescriptionArgs([]string{"Planning"})
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if len(tags) != 0 || description != "Planning" {
+			t.Errorf("expected no tags and a description, got %v and '%s'", tags, description)
+		}
+	})
+
+	t.Run("with a tag as the last argument", func(t *testing.T) {
+		for _, args := range [][]string{{"+meeting"}, {"+meeting", "+billable"}, {}} {
+			_, _, err := timeslip.ParseTagDescriptionArgs(args)
+			if err == nil {
+				t.Fatalf("expected an error for %v", args)
+			}
+
+			if err.Error() != "a description is required after any tags" {
+				t.Errorf("unexpected error, got '%s'", err)
+			}
+		}
+	})
+}
+
+func TestSlip_NewWithTags(t *testing.T) {
+	t.Run("tags are stored without the prefix", func(t *testing.T) {
+		ts, err := timeslip.New("Acme.Api", "+meeting", "+billable", "+meeting")
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if fmt.Sprint(ts.Tags) != "[meeting billable]" {
+			t.Errorf("expected unique tags, got %v", ts.Tags)
+		}
+
+		if !ts.HasTag("Meeting") {
+			t.Error("expected tag lookup to ignore case")
+		}
+	})
+
+	t.Run("tags are stored in lower case", func(t *testing.T) {
+		ts, err := timeslip.New("Acme.Api", "+Meeting", "+meeting", "+BILLABLE")
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if fmt.Sprint(ts.Tags) != "[meeting billable]" {
+			t.Errorf("expected lower case tags, got %v", ts.Tags)
+		}
+	})
+
+	t.Run("saved tags are read in lower case", func(t *testing.T) {
+		ts := &timeslip.Slip{}
+		if err := timeslip.Unmarshal([]byte(`{"project":"Acme","tags":["Meeting","meeting"]}`), ts); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if fmt.Sprint(ts.Tags) != "[meeting]" {
+			t.Errorf("expected lower case tags, got %v", ts.Tags)
+		}
+	})
+
+	t.Run("with a bad tag name", func(t *testing.T) {
+		_, err := timeslip.New("Acme.Api", "+bad tag")
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+
+		if err.Error() != "bad tag format, got 'bad tag'" {
+			t.Errorf("unexpected error, got '%s'", err)
+		}
+	})
+
+	t.Run("tags are kept when done", func(t *testing.T) {
+		ts, _ := timeslip.New("Acme.Api", "+support")
+		ts.Done("Tagged timeslip")
+
+		if !ts.HasTag("support") {
+			t.Errorf("expected tags to be kept, got %v", ts.Tags)
+		}
+	})
+
+	t.Run("tags are included in the output", func(t *testing.T) {
+		ts, _ := timeslip.New("Acme.Api", "+support")
+
+		if !strings.HasPrefix(ts.String(), "Acme.Api +support | Started:") {
+			t.Errorf("expected tags in the output, got '%s'", ts.String())
+		}
+	})
+}
+
+func TestSlip_TransitionsAt(t *testing.T) {
+	now := time.Now()
+	ago := func(seconds int) time.Time { return now.Add(-time.Duration(seconds) * time.Second) }
+
+	t.Run("starting in the past", func(t *testing.T) {
+		ts, err := timeslip.NewAt(ago(600), "Project.Backdated")
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if ts.Started != int(ago(600).Unix()) || ts.Modified != ts.Started {
+			t.Errorf("expected started/modified to be back-dated, got %d/%d", ts.Started, ts.Modified)
+		}
+	})
+
+	t.Run("starting in the future", func(t *testing.T) {
+		_, err := timeslip.NewAt(now.Add(time.Hour), "Project.Future")
+		if err == nil || err.Error() != "time can not be in the future" {
+			t.Errorf("expected a future time error, got '%v'", err)
+		}
+	})
+
+	t.Run("pause, resume and done in the past", func(t *testing.T) {
+		ts, _ := timeslip.NewAt(ago(600), "Project.Backdated")
+
+		if err := ts.PauseAt(ago(500)); err != nil {
+			t.Fatalf("unexpected pause error, got '%s'", err)
+		}
+		if err := ts.ResumeAt(ago(300)); err != nil {
+			t.Fatalf("unexpected resume error, got '%s'", err)
+		}
+		if err := ts.DoneAt(ago(100), "Back-dated"); err != nil {
+			t.Fatalf("unexpected done error, got '%s'", err)
+		}
+
+		if ts.Worked != 300 {
+			t.Errorf("expected 300 seconds worked, got %d", ts.Worked)
+		}
+		if ts.Finished != int(ago(100).Unix()) {
+			t.Errorf("expected finished time to be back-dated, got %d", ts.Finished)
+		}
+	})
+
+	t.Run("pausing before the last resume", func(t *testing.T) {
+		ts, _ := timeslip.NewAt(ago(600), "Project.OutOfOrder")
+		_ = ts.PauseAt(ago(500))
+		_ = ts.ResumeAt(ago(300))
+
+		err := ts.PauseAt(ago(400))
+		if err == nil || !strings.HasPrefix(err.Error(), "time can not be before the last change") {
+			t.Errorf("expected an ordering error, got '%v'", err)
+		}
+		if ts.Status != status.Resumed {
+			t.Errorf("expected status to be unchanged, got '%s'", ts.Status)
+		}
+	})
+
+	t.Run("resuming in the future", func(t *testing.T) {
+		ts, _ := timeslip.NewAt(ago(600), "Project.Future")
+		_ = ts.PauseAt(ago(500))
+
+		if err := ts.ResumeAt(now.Add(time.Minute)); err == nil {
+			t.Error("expected a future time error")
+		}
+	})
+}
+
+func TestSlip_NewCompleted(t *testing.T) {
+	to := time.Now().Add(-time.Hour)
+	from := to.Add(-150 * time.Minute)
+
+	t.Run("with valid times", func(t *testing.T) {
+		ts, err := timeslip.NewCompleted("Project.Manual", from, to, "Logged later", "+meeting")
+		if err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		if ts.Status != status.Completed {
+			t.Errorf("expected '%s' status, got '%s'", status.Completed, ts.Status)
+		}
+		if ts.Started != int(from.Unix()) || ts.Finished != int(to.Unix()) {
+			t.Errorf("unexpected started/finished times, got %d/%d", ts.Started, ts.Finished)
+		}
+		if ts.Worked != 150*60 {
+			t.Errorf("expected 150 minutes worked, got %d seconds", ts.Worked)
+		}
+		if ts.Description != "Logged later" || ts.UUID == "" || !ts.HasTag("meeting") {
+			t.Errorf("expected description, UUID, and tags to be set, got %+v", ts)
+		}
+	})
+
+	t.Run("when finished before started", func(t *testing.T) {
+		_, err := timeslip.NewCompleted("Project.Manual", to, from, "Backwards")
+		if err == nil || err.Error() != "finish time must be after the start time" {
+			t.Errorf("expected an ordering error, got '%v'", err)
+		}
+	})
+
+	t.Run("when finished in the future", func(t *testing.T) {
+		_, err := timeslip.NewCompleted("Project.Manual", from, time.Now().Add(time.Hour), "Future")
+		if err == nil || err.Error() != "time can not be in the future" {
+			t.Errorf("expected a future time error, got '%v'", err)
+		}
+	})
+}
+
+func TestSlip_TimeWorkedBetween(t *testing.T) {
+	friday := time.Date(2026, time.October, 9, 0, 0, 0, 0, time.Local)
+	monday := friday.AddDate(0, 0, 3)
+	hour := 60 * 60
+
+	t.Run("with segments across the weekend", func(t *testing.T) {
+		slip := timeslip.Slip{
+			Started:  int(friday.Unix()) + 20*hour,
+			Finished: int(monday.Unix()) + 10*hour,
+			Status:   status.Completed,
+			Segments: []timeslip.Segment{
+				{Start: int(friday.Unix()) + 20*hour, End: int(friday.Unix()) + 22*hour},
+				{Start: int(monday.Unix()) + 9*hour, End: int(monday.Unix()) + 10*hour},
+			},
+		}
+		slip.Worked = 3 * hour
+
+		if worked := slip.TimeWorkedBetween(friday, monday); worked != 2*hour {
+			t.Errorf("expected 2 hours in the first week, got %d seconds", worked)
+		}
+		if worked := slip.TimeWorkedBetween(monday, monday.AddDate(0, 0, 7)); worked != hour {
+			t.Errorf("expected 1 hour in the second week, got %d seconds", worked)
+		}
+	})
+
+	t.Run("without segments spreads the time evenly", func(t *testing.T) {
+		slip := timeslip.Slip{
+			Started:  int(friday.Unix()),
+			Finished: int(monday.Unix()) + 24*hour,
+			Worked:   8 * hour,
+			Status:   status.Completed,
+		}
+
+		if worked := slip.TimeWorkedBetween(friday, monday); worked != 6*hour {
+			t.Errorf("expected 6 hours before Monday, got %d seconds", worked)
+		}
+		if worked := slip.TimeWorkedBetween(monday, monday.AddDate(0, 0, 7)); worked != 2*hour {
+			t.Errorf("expected 2 hours from Monday, got %d seconds", worked)
+		}
+	})
+
+	t.Run("outside the time range", func(t *testing.T) {
+		slip := timeslip.Slip{
+			Started:  int(friday.Unix()),
+			Finished: int(friday.Unix()) + hour,
+			Status:   status.Completed,
+			Segments: []timeslip.Segment{{Start: int(friday.Unix()), End: int(friday.Unix()) + hour}},
+		}
+		slip.Worked = hour
+
+		if worked := slip.TimeWorkedBetween(monday, monday.AddDate(0, 0, 7)); worked != 0 {
+			t.Errorf("expected no time worked, got %d seconds", worked)
+		}
+	})
+}
+
+func TestSlip_Validate(t *testing.T) {
+	valid := func() timeslip.Slip {
+		return timeslip.Slip{
+			Project:  "Project",
+			Task:     "Task",
+			Started:  100,
+			Worked:   30,
+			Finished: 200,
+			Status:   status.Completed,
+			UUID:     "0d8e895e-d3db-4887-86e3-8bb7f63ba101",
+			Segments: []timeslip.Segment{{Start: 100, End: 110}, {Start: 180, End: 200}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *timeslip.Slip)
+		err    string
+	}{
+		{"when valid", func(s *timeslip.Slip) {}, ""},
+		{"when not completed", func(s *timeslip.Slip) { s.Status = status.Paused }, "timeslip status must be 'completed', got 'paused'"},
+		{"when missing the project", func(s *timeslip.Slip) { s.Project = "" }, "bad Project/Task name, got '.Task'"},
+		{"when missing the UUID", func(s *timeslip.Slip) { s.UUID = "" }, "timeslip is missing a UUID"},
+		{"when finished before started", func(s *timeslip.Slip) { s.Finished = 50 }, "finish time must be after the start time"},
+		{"when worked is negative", func(s *timeslip.Slip) { s.Worked = -1 }, "worked time can not be negative"},
+		{"when worked exceeds the elapsed time", func(s *timeslip.Slip) { s.Worked = 101; s.Segments = nil }, "worked time can not be more than the time between the start and finish"},
+		{"when a segment is outside the times", func(s *timeslip.Slip) { s.Segments[1].End = 210 }, "segments must be in order, between the start and finish times"},
+		{"when the segments do not match worked", func(s *timeslip.Slip) { s.Worked = 40 }, "segments total 30s, expected the worked time of 40s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := valid()
+			tt.change(&ts)
+
+			err := ts.Validate()
+			if tt.err == "" && err != nil {
+				t.Errorf("unexpected error, got '%s'", err)
+			} else if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Errorf("expected error '%s', got '%v'", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestSlip_Retime(t *testing.T) {
+	t.Run("segments are clipped and refitted", func(t *testing.T) {
+		ts := timeslip.Slip{
+			Project:  "Project",
+			Started:  100,
+			Worked:   30,
+			Finished: 200,
+			Status:   status.Completed,
+			UUID:     "0d8e895e-d3db-4887-86e3-8bb7f63ba101",
+			Segments: []timeslip.Segment{{Start: 100, End: 110}, {Start: 180, End: 200}},
+		}
+
+		if err := ts.Retime(105, 190, 25); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+
+		expected := []timeslip.Segment{{Start: 105, End: 110}, {Start: 170, End: 190}}
+		if fmt.Sprint(ts.Segments) != fmt.Sprint(expected) {
+			t.Errorf("expected segments %v, got %v", expected, ts.Segments)
+		}
+		if err := ts.Validate(); err != nil {
+			t.Errorf("expected a valid timeslip, got '%s'", err)
+		}
+	})
+
+	t.Run("legacy timeslips have no segments added", func(t *testing.T) {
+		ts := timeslip.Slip{Project: "Project", Started: 100, Worked: 30, Finished: 200, Status: status.Completed, UUID: "uuid"}
+
+		if err := ts.Retime(100, 200, 60); err != nil {
+			t.Fatalf("unexpected error, got '%s'", err)
+		}
+		if ts.Worked != 60 || len(ts.Segments) != 0 {
+			t.Errorf("expected 60 seconds worked without segments, got %+v", ts)
+		}
+	})
+
+	t.Run("when the worked time is too long", func(t *testing.T) {
+		ts := timeslip.Slip{Project: "Project", Started: 100, Worked: 30, Finished: 200, Status: status.Completed, UUID: "uuid"}
+
+		if err := ts.Retime(100, 120, 30); err == nil {
+			t.Error("expected an error")
+		}
+		if ts.Finished != 200 {
+			t.Errorf("expected the timeslip to be unchanged, got %+v", ts)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package worked
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoundingMode is the direction in which worked time is rounded.
+type RoundingMode string
+
+const (
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+	RoundNearest RoundingMode = "nearest" // halfway values are rounded up
+)
+
+// ParseRoundingMode returns the RoundingMode for the name.
+func ParseRoundingMode(name string) (RoundingMode, error) {
+	switch m := RoundingMode(strings.ToLower(name)); m {
+	case RoundUp, RoundDown, RoundNearest:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode, got '%s'. Expected: up, down, or nearest", name)
+	}
+}
+
+// RoundSeconds rounds the seconds to a multiple of the increment, which is
+// also in seconds. No rounding is done for an increment of zero or less.
+func RoundSeconds(seconds, increment int, mode RoundingMode) int {
+	if increment <= 0 {
+		return seconds
+	}
+
+	remainder := seconds % increment
+	if remainder < 0 {
+		remainder += increment
+	}
+	if remainder == 0 {
+		return seconds
+	}
+
+	down := seconds - remainder
+
+	switch mode {
+	case RoundUp:
+		return down + increment
+	case RoundDown:
+		return down
+	default:
+		if remainder*2 >= increment {
+			return down + increment
+		}
+		return down
+	}
+}
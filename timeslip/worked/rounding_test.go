@@ -0,0 +1,47 @@
+package worked_test
+
+import (
+	"testing"
+
+	"github.com/mrcook/time_warrior/timeslip/worked"
+)
+
+func TestRoundSeconds(t *testing.T) {
+	quarter := 15 * 60
+
+	tests := []struct {
+		seconds   int
+		increment int
+		mode      worked.RoundingMode
+		expected  int
+	}{
+		{60, quarter, worked.RoundUp, quarter},
+		{quarter, quarter, worked.RoundUp, quarter},
+		{quarter + 1, quarter, worked.RoundUp, 2 * quarter},
+		{quarter - 1, quarter, worked.RoundDown, 0},
+		{2*quarter + 60, quarter, worked.RoundDown, 2 * quarter},
+		{7 * 60, quarter, worked.RoundNearest, 0},
+		{450, quarter, worked.RoundNearest, quarter}, // halfway
+		{23 * 60, quarter, worked.RoundNearest, 2 * quarter},
+		{4 * 60, 6 * 60, worked.RoundNearest, 6 * 60},
+		{1234, 0, worked.RoundUp, 1234},
+	}
+
+	for _, test := range tests {
+		output := worked.RoundSeconds(test.seconds, test.increment, test.mode)
+		if output != test.expected {
+			t.Errorf("Expected %ds rounded %s to %ds to be %d, got %d", test.seconds, test.mode, test.increment, test.expected, output)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	m, err := worked.ParseRoundingMode("Up")
+	if err != nil || m != worked.RoundUp {
+		t.Errorf("Expected the up rounding mode, got '%s', %v", m, err)
+	}
+
+	if _, err := worked.ParseRoundingMode("sideways"); err == nil {
+		t.Error("Expected an unknown rounding mode error")
+	}
+}
@@ -4,6 +4,7 @@ package worked
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 )
@@ -37,62 +38,219 @@ func (w *WorkTime) FromSeconds(seconds int) {
 	w.Seconds = remainder % 60
 }
 
-// FromString parses an adjust command string, e.g. `-75m`.
-func (w *WorkTime) FromString(adjustment string) error {
-	adjustment = strings.TrimSpace(adjustment)
+// FromString parses a duration string, e.g. `-75m`, with an optional +/- sign.
+//
+// The accepted formats are:
+//
+//   - one or more numbers with a time unit, in the order: d, h, m, s.
+//     e.g. `2h15m30s`, `1.5h`, `1d` (a day being 24 hours).
+//   - a clock duration of hours and minutes, and optional seconds: `01:30`.
+//   - a number without a time unit, which is taken as minutes: `90`.
+func (w *WorkTime) FromString(duration string) error {
+	duration = strings.TrimSpace(duration)
 
-	if strings.Contains(adjustment, " ") {
+	if strings.Contains(duration, " ") {
 		return fmt.Errorf("invalid time unit, should not contain spaces")
 	}
 
-	unit := adjustment[len(adjustment)-1:]
-	value := adjustment[:len(adjustment)-1]
+	value := strings.TrimLeft(duration, "+-")
+	if value == "" {
+		return fmt.Errorf("missing duration")
+	}
+	if len(duration)-len(value) > 1 {
+		return fmt.Errorf("invalid duration, got '%s'", duration)
+	}
 
-	if len(unit) != 1 || !strings.ContainsAny(unit, "hms") {
-		return fmt.Errorf("invalid time unit, got '%s'", unit)
+	var seconds float64
+	var err error
+
+	switch {
+	case strings.Contains(value, ":"):
+		seconds, err = parseClockDuration(value)
+	case isNumber(value):
+		minutes, _ := strconv.ParseFloat(value, 64)
+		seconds = minutes * 60
+	default:
+		seconds, err = parseUnitDuration(value)
 	}
 
-	hasNegative := value[0] == '-'
-	hasPositive := value[0] == '+'
+	if err != nil {
+		return err
+	}
 
-	if hasNegative || hasPositive {
-		value = adjustment[1 : len(adjustment)-1]
+	total := int(math.Round(seconds))
+	if duration[0] == '-' {
+		total = -total
 	}
+	w.FromSeconds(total)
+
+	return nil
+}
+
+// Number of seconds for each time unit, in the order they must be given.
+var timeUnits = []struct {
+	unit    byte
+	seconds float64
+}{
+	{'d', 24 * 60 * 60},
+	{'h', 60 * 60},
+	{'m', 60},
+	{'s', 1},
+}
+
+// Parses a duration of numbers with time units, e.g. `2h15m30s`.
+func parseUnitDuration(value string) (float64, error) {
+	var seconds float64
+	next := 0 // index of the next allowed time unit
+
+	for len(value) > 0 {
+		i := 0
+		for i < len(value) && (isDigit(value[i]) || value[i] == '.') {
+			i++
+		}
 
-	switch unit {
-	case "h":
-		h, err := strconv.Atoi(value)
-		if err != nil {
-			return fmt.Errorf("unable to process input")
+		number := value[:i]
+		if number == "" || !isNumber(number) {
+			return 0, fmt.Errorf("unable to process input")
 		}
-		w.FromHours(h)
-	case "m":
-		m, err := strconv.Atoi(value)
-		if err != nil {
-			return fmt.Errorf("unable to process input")
+		if i == len(value) {
+			return 0, fmt.Errorf("missing time unit after '%s'", number)
+		}
+
+		unit := value[i]
+		found := false
+		for next < len(timeUnits) && !found {
+			found = timeUnits[next].unit == unit
+			next++
+		}
+		if !found {
+			if !strings.ContainsRune("dhms", rune(unit)) {
+				return 0, fmt.Errorf("invalid time unit, got '%c'", unit)
+			}
+			return 0, fmt.Errorf("time units must be given once, in the order: d, h, m, s")
+		}
+
+		n, _ := strconv.ParseFloat(number, 64)
+		seconds += n * timeUnits[next-1].seconds
+
+		value = value[i+1:]
+	}
+
+	return seconds, nil
+}
+
+// Parses a clock duration of `HH:MM` or `HH:MM:SS`.
+func parseClockDuration(value string) (float64, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid clock duration, expected 'HH:MM', got '%s'", value)
+	}
+
+	var seconds float64
+	multiplier := 3600.0
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && (n > 59 || len(part) != 2)) {
+			return 0, fmt.Errorf("invalid clock duration, expected 'HH:MM', got '%s'", value)
 		}
-		w.FromMinutes(m)
-	case "s":
-		s, err := strconv.Atoi(value)
-		if err != nil {
-			return fmt.Errorf("unable to process input")
+		seconds += float64(n) * multiplier
+		multiplier /= 60
+	}
+
+	return seconds, nil
+}
+
+// Returns true for an unsigned integer or decimal number.
+func isNumber(value string) bool {
+	if value == "" || value[0] == '.' || value[len(value)-1] == '.' {
+		return false
+	}
+
+	dots := 0
+	for i := 0; i < len(value); i++ {
+		if value[i] == '.' {
+			dots++
+		} else if !isDigit(value[i]) {
+			return false
 		}
-		w.FromSeconds(s)
+	}
+	return dots <= 1
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Format is a named style for displaying the worked time.
+type Format string
+
+const (
+	Default Format = "default" // e.g. `1h 10m`, `10m 14s`, `22 minutes`
+	Decimal Format = "decimal" // decimal hours, e.g. `1.17`
+	Clock   Format = "clock"   // hours and minutes, e.g. `01:10`
+	ISO8601 Format = "iso8601" // ISO 8601 duration, e.g. `PT1H10M`
+)
+
+// DisplayFormat is the format used by String, and for displaying worked
+// time in reports.
+var DisplayFormat = Default
+
+// ParseFormat returns the Format for the style name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case Default, Decimal, Clock, ISO8601:
+		return f, nil
+	case "":
+		return Default, nil
 	default:
-		return fmt.Errorf("unable to process input")
+		return "", fmt.Errorf("unknown duration format, got '%s'. Expected: default, decimal, clock, or iso8601", name)
 	}
+}
+
+// Format returns the worked time as a string in the given format.
+func (w *WorkTime) Format(f Format) string {
+	seconds := w.ToSeconds()
 
-	if hasNegative {
-		w.Hours = -w.Hours
-		w.Minutes = -w.Minutes
-		w.Seconds = -w.Seconds
+	sign := ""
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
 	}
+	hours, minutes := seconds/3600, seconds%3600/60
 
-	return nil
+	switch f {
+	case Decimal:
+		return fmt.Sprintf("%s%.2f", sign, float64(seconds)/3600)
+	case Clock:
+		return fmt.Sprintf("%s%02d:%02d", sign, hours, minutes)
+	case ISO8601:
+		if seconds == 0 {
+			return "PT0S"
+		}
+		iso := sign + "PT"
+		if hours != 0 {
+			iso += fmt.Sprintf("%dH", hours)
+		}
+		if minutes != 0 {
+			iso += fmt.Sprintf("%dM", minutes)
+		}
+		if seconds%60 != 0 {
+			iso += fmt.Sprintf("%dS", seconds%60)
+		}
+		return iso
+	default:
+		return w.defaultString()
+	}
 }
 
-// String returns the worked time as a string, e.g. `1h 10m`.
+// String returns the worked time as a string using the DisplayFormat,
+// by default this is e.g. `1h 10m`.
 func (w *WorkTime) String() string {
+	return w.Format(DisplayFormat)
+}
+
+func (w *WorkTime) defaultString() string {
 	if w.Hours != 0 && w.Minutes != 0 {
 		return fmt.Sprintf("%dh %dm", w.Hours, w.Minutes)
 	} else if w.Minutes != 0 && w.Seconds != 0 {
@@ -37,26 +37,66 @@ func TestFromSeconds(t *testing.T) {
 }
 
 func TestFromInvalidString(t *testing.T) {
-	st := worked.WorkTime{}
-
-	err := st.FromString("3h 52m 18s")
-	if err.Error() != "invalid time unit, should not contain spaces" {
-		t.Error("Expected an invalid string error")
-	}
-
-	err = st.FromString("3h52m")
-	if err.Error() != "unable to process input" {
-		t.Error("Expected an unable to process error")
-	}
-
-	err = st.FromString("5")
-	if err.Error() != "invalid time unit, got '5'" {
-		t.Errorf("Expected invalid time unit error, got '%s'", err)
+	tests := map[string]string{
+		"3h 52m 18s": "invalid time unit, should not contain spaces",
+		"":           "missing duration",
+		"-":          "missing duration",
+		"--5m":       "invalid duration, got '--5m'",
+		"3h52":       "missing time unit after '52'",
+		"5x":         "invalid time unit, got 'x'",
+		"30m2h":      "time units must be given once, in the order: d, h, m, s",
+		"2h2h":       "time units must be given once, in the order: d, h, m, s",
+		"1..5h":      "unable to process input",
+		"h":          "unable to process input",
+		"1:5":        "invalid clock duration, expected 'HH:MM', got '1:5'",
+		"01:75":      "invalid clock duration, expected 'HH:MM', got '01:75'",
+		"1:30:00:00": "invalid clock duration, expected 'HH:MM', got '1:30:00:00'",
+	}
+
+	for input, message := range tests {
+		t.Run(input, func(t *testing.T) {
+			st := worked.WorkTime{}
+
+			err := st.FromString(input)
+			if err == nil {
+				t.Fatalf("Expected an error for '%s'", input)
+			}
+			if err.Error() != message {
+				t.Errorf("Expected '%s' error, got '%s'", message, err)
+			}
+		})
 	}
+}
 
-	err = st.FromString("-9")
-	if err.Error() != "invalid time unit, got '9'" {
-		t.Errorf("Expected invalid time unit error, got '%s'", err)
+func TestFromCompoundString(t *testing.T) {
+	tests := map[string]int{
+		"2h15m30s": 2*3600 + 15*60 + 30,
+		"1h30m":    5400,
+		"-1h30m":   -5400,
+		"+45m10s":  2710,
+		"1.5h":     5400,
+		"0.25h":    900,
+		"1.5m":     90,
+		"1d2h":     26 * 3600,
+		"90":       5400,
+		"7.5":      450,
+		"-20":      -1200,
+		"01:30":    5400,
+		"1:05:09":  3909,
+		"-00:45":   -2700,
+	}
+
+	for input, seconds := range tests {
+		t.Run(input, func(t *testing.T) {
+			st := worked.WorkTime{}
+
+			if err := st.FromString(input); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if st.ToSeconds() != seconds {
+				t.Errorf("Expected %d seconds, got %d", seconds, st.ToSeconds())
+			}
+		})
 	}
 }
 
@@ -247,3 +287,46 @@ func TestSubtract(t *testing.T) {
 		t.Errorf("Expected 6484 to be returned, got %d", st.ToSeconds())
 	}
 }
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		seconds  int
+		format   worked.Format
+		expected string
+	}{
+		{4200, worked.Default, "1h 10m"},
+		{4200, worked.Decimal, "1.17"},
+		{4200, worked.Clock, "01:10"},
+		{4200, worked.ISO8601, "PT1H10M"},
+		{-2730, worked.Decimal, "-0.76"},
+		{-2730, worked.Clock, "-00:45"},
+		{-2730, worked.ISO8601, "-PT45M30S"},
+		{0, worked.ISO8601, "PT0S"},
+		{363600, worked.Clock, "101:00"},
+	}
+
+	for _, test := range tests {
+		st := worked.WorkTime{}
+		st.FromSeconds(test.seconds)
+
+		if output := st.Format(test.format); output != test.expected {
+			t.Errorf("Expected '%s' for %d seconds in %s format, got '%s'", test.expected, test.seconds, test.format, output)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := worked.ParseFormat("ISO8601")
+	if err != nil || f != worked.ISO8601 {
+		t.Errorf("Expected the iso8601 format, got '%s', %v", f, err)
+	}
+
+	f, err = worked.ParseFormat("")
+	if err != nil || f != worked.Default {
+		t.Errorf("Expected the default format, got '%s', %v", f, err)
+	}
+
+	if _, err = worked.ParseFormat("fortnights"); err == nil {
+		t.Error("Expected an unknown format error")
+	}
+}